@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+
+	"github.com/simonhull/kitsune/internal/db"
+)
+
+// exportTrack is one track in a library export, with the kitsune-specific
+// metadata the server doesn't know about.
+type exportTrack struct {
+	ID             string `json:"id"`
+	Title          string `json:"title"`
+	Artist         string `json:"artist"`
+	ArtistID       string `json:"artist_id"`
+	Album          string `json:"album"`
+	AlbumID        string `json:"album_id"`
+	Year           int    `json:"year,omitempty"`
+	Disc           int    `json:"disc,omitempty"`
+	Track          int    `json:"track,omitempty"`
+	DurationMs     int    `json:"duration_ms"`
+	Genre          string `json:"genre,omitempty"`
+	Format         string `json:"format,omitempty"`
+	BitRate        int    `json:"bitrate,omitempty"`
+	ShuffleExclude bool   `json:"shuffle_exclude"`
+	LinkedNextID   string `json:"linked_next_id,omitempty"`
+	Rating         int    `json:"rating,omitempty"`
+	PlayCount      int    `json:"play_count,omitempty"`
+}
+
+var exportColumns = []string{
+	"id", "title", "artist", "artist_id", "album", "album_id", "year", "disc", "track",
+	"duration_ms", "genre", "format", "bitrate", "shuffle_exclude", "linked_next_id",
+	"rating", "play_count",
+}
+
+func (t exportTrack) record() []string {
+	return []string{
+		t.ID, t.Title, t.Artist, t.ArtistID, t.Album, t.AlbumID, strconv.Itoa(t.Year),
+		strconv.Itoa(t.Disc), strconv.Itoa(t.Track), strconv.Itoa(t.DurationMs), t.Genre,
+		t.Format, strconv.Itoa(t.BitRate), strconv.FormatBool(t.ShuffleExclude), t.LinkedNextID,
+		strconv.Itoa(t.Rating), strconv.Itoa(t.PlayCount),
+	}
+}
+
+// runExportLibrary implements "kitsune export-library": it writes every
+// track in the local library as JSON (an array) or CSV.
+func runExportLibrary(args []string, logger *slog.Logger) error {
+	fs := flag.NewFlagSet("export-library", flag.ContinueOnError)
+	format := fs.String("format", "json", "output format: json or csv")
+	out := fs.String("o", "", "write to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *format != "json" && *format != "csv" {
+		return fmt.Errorf("unknown format %q (want json or csv)", *format)
+	}
+
+	database, err := db.Open(logger)
+	if err != nil {
+		return fmt.Errorf("database: %w", err)
+	}
+	defer database.Close()
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	bw := bufio.NewWriter(w)
+
+	if *format == "csv" {
+		err = exportCSV(database, bw)
+	} else {
+		err = exportJSON(database, bw)
+	}
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func exportJSON(database *db.DB, w io.Writer) error {
+	enc := json.NewEncoder(w)
+	sep := "[\n"
+	err := database.EachTrack(func(t db.TrackRow) error {
+		if _, err := io.WriteString(w, sep); err != nil {
+			return err
+		}
+		sep = ","
+		return enc.Encode(toExportTrack(t))
+	})
+	if err != nil {
+		return err
+	}
+	if sep != "," {
+		sep = "[" // an empty library
+	} else {
+		sep = ""
+	}
+	_, err = io.WriteString(w, sep+"]\n")
+	return err
+}
+
+func exportCSV(database *db.DB, w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(exportColumns); err != nil {
+		return err
+	}
+	err := database.EachTrack(func(t db.TrackRow) error {
+		return cw.Write(toExportTrack(t).record())
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func toExportTrack(t db.TrackRow) exportTrack {
+	return exportTrack{
+		ID:             t.ID,
+		Title:          t.Title,
+		Artist:         t.Artist,
+		ArtistID:       t.ArtistID,
+		Album:          t.Album,
+		AlbumID:        t.AlbumID,
+		Year:           t.Year,
+		Disc:           t.DiscNum,
+		Track:          t.TrackNum,
+		DurationMs:     t.DurationMs,
+		Genre:          t.Genre,
+		Format:         t.Format,
+		BitRate:        t.BitRate,
+		ShuffleExclude: t.ShuffleExclude,
+		LinkedNextID:   t.LinkedNextID,
+		Rating:         t.Rating,
+		PlayCount:      t.PlayCount,
+	}
+}
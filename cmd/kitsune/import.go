@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/simonhull/kitsune/internal/db"
+	"github.com/simonhull/kitsune/internal/importer"
+)
+
+// runImportStats implements "kitsune import-stats": it merges ratings and
+// play counts from a file into the local library and reports the matches.
+func runImportStats(args []string, logger *slog.Logger) error {
+	fs := flag.NewFlagSet("import-stats", flag.ContinueOnError)
+	format := fs.String("format", "kitsune", "input format: kitsune, listenbrainz or lastfm")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: kitsune import-stats [--format kitsune|listenbrainz|lastfm] FILE")
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	recs, err := importer.Parse(f, importer.Format(*format))
+	if err != nil {
+		return err
+	}
+
+	database, err := db.Open(logger)
+	if err != nil {
+		return fmt.Errorf("database: %w", err)
+	}
+	defer database.Close()
+
+	res, err := importer.Apply(database, recs)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d matched, %d skipped\n", res.Matched, res.Skipped)
+	return nil
+}
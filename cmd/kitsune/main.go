@@ -2,19 +2,34 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"log/slog"
+	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/simonhull/kitsune/internal/app"
 	"github.com/simonhull/kitsune/internal/config"
+	"github.com/simonhull/kitsune/internal/control"
 	"github.com/simonhull/kitsune/internal/db"
 	"github.com/simonhull/kitsune/internal/player"
 	"github.com/simonhull/kitsune/internal/subsonic"
 )
 
 func main() {
+	// Subcommands work on the local database without starting the UI.
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			if err := run(os.Args[2:], slog.New(slog.NewTextHandler(io.Discard, nil))); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", os.Args[1], err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	cfg, err := config.Load()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "config error: %v\n", err)
@@ -22,7 +37,7 @@ func main() {
 	}
 
 	// Log to file so it doesn't corrupt the TUI.
-	logger := setupLogger()
+	logger := setupLogger(cfg.Subsonic.Debug || os.Getenv("KITSUNE_DEBUG") != "")
 
 	database, err := db.Open(logger)
 	if err != nil {
@@ -30,11 +45,30 @@ func main() {
 		os.Exit(1)
 	}
 	defer database.Close()
+	database.SetTrackOrder(db.TrackOrder(cfg.UI.TrackOrder))
+	if err := database.SetAccentFolding(!cfg.UI.SearchExactAccents); err != nil {
+		logger.Warn("search index", "err", err)
+	}
 
 	// Create Subsonic client if configured.
 	var client *subsonic.Client
 	if cfg.HasSubsonic() {
-		client = subsonic.NewClient(cfg.Subsonic.URL, cfg.Subsonic.Username, cfg.Subsonic.Password)
+		if cfg.Subsonic.InsecureSkipVerify {
+			fmt.Fprintln(os.Stderr, "WARNING: insecure_skip_verify is set; TLS certificates will NOT be verified")
+			logger.Warn("TLS certificate verification disabled by config")
+		}
+
+		client, err = subsonic.NewClient(cfg.Subsonic.URL, cfg.Subsonic.Username, cfg.Subsonic.Password, subsonic.Options{
+			Timeout:            time.Duration(cfg.Subsonic.TimeoutSec) * time.Second,
+			CACertPath:         cfg.Subsonic.CACert,
+			ProxyURL:           cfg.Subsonic.Proxy,
+			InsecureSkipVerify: cfg.Subsonic.InsecureSkipVerify,
+			Logger:             logger,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "subsonic client error: %v\n", err)
+			os.Exit(1)
+		}
 
 		if err := client.Ping(); err != nil {
 			fmt.Fprintf(os.Stderr, "subsonic connection failed: %v\n", err)
@@ -43,26 +77,52 @@ func main() {
 		}
 	}
 
-	// Initialize audio player.
-	p, err := player.New(logger)
+	// Initialize audio player, streaming over the Subsonic client's transport.
+	var streamHTTP *http.Client
+	if client != nil {
+		streamHTTP = client.StreamHTTPClient()
+	}
+	p, err := player.New(cfg.Player, streamHTTP, logger)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "audio init failed: %v\n", err)
 		os.Exit(1)
 	}
 
-	prog := tea.NewProgram(
-		app.New(cfg, database, client, p),
+	// Optional HTTP status/command server; commands go through the program.
+	var prog *tea.Program
+	var ctl *control.Server
+	if cfg.Control.HTTPAddr != "" {
+		ctl = control.NewServer(cfg.Control, p.Elapsed, func(msg any) { prog.Send(msg) }, logger)
+	}
+
+	prog = tea.NewProgram(
+		app.New(cfg, database, client, p, ctl),
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
+		tea.WithReportFocus(),
 	)
 
+	if ctl != nil {
+		if err := ctl.Start(); err != nil {
+			fmt.Fprintf(os.Stderr, "control server failed: %v\n", err)
+			os.Exit(1)
+		}
+		defer ctl.Close()
+	}
+
 	if _, err := prog.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
-func setupLogger() *slog.Logger {
+// subcommands are the command-line tools run as "kitsune <name> [flags]".
+var subcommands = map[string]func(args []string, logger *slog.Logger) error{
+	"export-library": runExportLibrary,
+	"import-stats":   runImportStats,
+}
+
+func setupLogger(debug bool) *slog.Logger {
 	logDir := db.DataDir()
 	os.MkdirAll(logDir, 0o755)
 
@@ -73,7 +133,11 @@ func setupLogger() *slog.Logger {
 		return slog.New(slog.NewTextHandler(os.NewFile(0, os.DevNull), nil))
 	}
 
-	logger := slog.New(slog.NewTextHandler(f, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	level := slog.LevelInfo
+	if debug {
+		level = slog.LevelDebug
+	}
+	logger := slog.New(slog.NewTextHandler(f, &slog.HandlerOptions{Level: level}))
 	slog.SetDefault(logger)
 	return logger
 }
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/simonhull/kitsune/internal/db"
+)
+
+// albumSortNames label the albums list's orders in the OSD.
+var albumSortNames = map[db.AlbumSort]string{
+	db.AlbumSortArtist: "Albums by artist",
+	db.AlbumSortYear:   "Albums by year",
+	db.AlbumSortRecent: "Albums, recently added",
+	db.AlbumSortPlayed: "Albums, most played",
+}
+
+// toggleAlbumsView switches the browser between the artist tree and the
+// flat list of all albums, in the order last used.
+func (m *Model) toggleAlbumsView() tea.Cmd {
+	if m.content.AlbumSort() != "" {
+		m.content.ShowTree()
+		return m.flashOSD(m.text(msgArtistsView))
+	}
+	return m.showAlbums(m.albumSort)
+}
+
+// cycleAlbumSort moves the albums list on to its next order.
+func (m *Model) cycleAlbumSort() tea.Cmd {
+	cur := m.content.AlbumSort()
+	if cur == "" {
+		return nil
+	}
+	i := slices.Index(db.AlbumSorts, cur)
+	return m.showAlbums(db.AlbumSorts[(i+1)%len(db.AlbumSorts)])
+}
+
+// showAlbums lists all albums in the given order and remembers it for the
+// next time the view is opened. Unknown orders sort by artist.
+func (m *Model) showAlbums(sort db.AlbumSort) tea.Cmd {
+	if !slices.Contains(db.AlbumSorts, sort) {
+		sort = db.AlbumSortArtist
+	}
+	if err := m.content.ShowAlbums(sort); err != nil {
+		m.playErr = fmt.Sprintf("albums: %v", err)
+		return nil
+	}
+	m.albumSort = sort
+	if m.nav != nil {
+		m.nav.ClearFilter()
+	}
+	return m.flashOSD(albumSortNames[sort])
+}
@@ -2,9 +2,11 @@ package app
 
 import (
 	"context"
+	"errors"
 	"math/rand/v2"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 	"time"
 
@@ -13,12 +15,17 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/simonhull/kitsune/internal/config"
+	"github.com/simonhull/kitsune/internal/control"
 	"github.com/simonhull/kitsune/internal/db"
 	"github.com/simonhull/kitsune/internal/player"
 	"github.com/simonhull/kitsune/internal/subsonic"
 	"github.com/simonhull/kitsune/internal/ui"
 )
 
+// Version is the kitsune release, set at build time with
+// -ldflags "-X github.com/simonhull/kitsune/internal/app.Version=...".
+var Version = "dev"
+
 type focus int
 
 const (
@@ -27,13 +34,19 @@ const (
 	focusQueue
 )
 
-type tickMsg time.Time
+// tickMsg refreshes the now playing position. Ticks carry the generation
+// they were started in so a restarted tick loop replaces the old one.
+type tickMsg struct{ id int }
 
-func tickCmd() tea.Cmd {
-	return tea.Tick(500*time.Millisecond, func(t time.Time) tea.Msg {
-		return tickMsg(t)
-	})
-}
+// frameMsg redraws the seek bar between ticks. Frames share the tick's
+// generation and stop with it.
+type frameMsg struct{ id int }
+
+// marqueeMsg scrolls a long artist name under the nav cursor one step.
+type marqueeMsg struct{}
+
+// marqueeInterval is how often a long artist name scrolls.
+const marqueeInterval = 250 * time.Millisecond
 
 type Model struct {
 	cfg     config.Config
@@ -44,6 +57,7 @@ type Model struct {
 	content *ui.ContentBrowser
 	queue   *ui.Queue
 	player  *player.Player
+	control *control.Server // nil unless the HTTP control server is enabled
 	focus   focus
 	styles  ui.Styles
 
@@ -51,75 +65,290 @@ type Model struct {
 	nowPlaying *ui.NowPlayingPanel
 	albumArt   *ui.AlbumArt
 	artData    []byte
-	artAlbumID string
+	artAlbumID string               // artKey of the track artData belongs to
+	artMisses  map[string]time.Time // artKey → when the server last said it had no art
+	links      ui.Linker            // web pages for artist and album names, if on
+	albumSort  db.AlbumSort         // order the albums view opens in
 
-	// Command palette.
+	// Overlays.
 	palette *ui.Palette
-
-	// Sync state.
+	info    *ui.Info
+	picker  *ui.Picker
+
+	// Picker state: what it's choosing, the song IDs waiting on an add, the
+	// playlist being browsed and the entries removed from it that the server
+	// has yet to drop, the duplicate tracks under review, and the podcast
+	// episodes listed.
+	pickerMode   pickerMode
+	pendingAdd   []string
+	openPlaylist *subsonic.PlaylistDetail
+	removals     []playlistRemoval
+	dupes        []db.TrackRow
+	episodes     []db.PodcastEpisode
+	// episodeSaved is when the playing episode's position was last saved.
+	episodeSaved time.Time
+
+	// queueArtist is the artist the browser last filled the queue from,
+	// for the "artist" on_select policy; anything else that replaces the
+	// queue clears it.
+	queueArtist string
+
+	// Listening time this session: listened counts playback progress, read
+	// from the player generation listenGen at listenPos and listenAt;
+	// listenSaved is how much of it is in the stored all-time total. The
+	// seek bar also draws from listenPos between ticks.
+	listened    time.Duration
+	listenSaved time.Duration
+	listenGen   uint64
+	listenPos   float64
+	listenAt    time.Time
+
+	// marks are the playing track's bookmarks, for the seek bar and picker.
+	marks []db.Bookmark
+
+	// Sync state. started is set once the startup view has been applied.
 	syncing bool
+	started bool
 	syncMsg string
 	syncErr string
 
 	// Player state.
-	paused  bool
-	playErr string
+	paused      bool
+	stopped     bool          // stopped by the user; the current track stays queued
+	resumeID    string        // track stopped mid-way, resumed at resumeAt
+	resumeAt    time.Duration // position resumeID was stopped at
+	playErr     string
+	tickID      int
+	retriedID   string    // track already retried after a truncated stream
+	transcodeID string    // track streamed transcoded after failing to decode part way
+	playGen     uint64    // player generation of the track currently playing
+	seekable    bool      // the playing track's stream can seek
+	levels      []float64 // band levels for the visualizer, read each tick
+	recent      []string  // recently played track IDs, oldest first
+
+	// Radio mode keeps the queue topped up with songs similar to the current track.
+	radio         bool
+	radioFetching bool
+	// nowPlayingOut is the line last written to the now playing file.
+	nowPlayingOut string
+
+	// continueArtist queues the artist's next album when their queue runs out.
+	continueArtist bool
+
+	// backdrop is the app's background, nil to leave the terminal's.
+	backdrop *ui.Backdrop
+
+	// topSongs caches each artist's top songs for the session, by artist ID.
+	topSongs map[string][]ui.QueueTrack
+
+	// remaining shows time left instead of the total on the seek bar.
+	remaining bool
+
+	// marqueeOn is set while a marqueeMsg loop is scrolling a nav name.
+	marqueeOn bool
+
+	// osd is transient feedback shown in the status bar until osdID's timer fires.
+	osd   string
+	osdID int
+
+	// count is a vim-style numeric prefix typed before a command (0 = none).
+	count int
+	// quitArmed is set by a first quit press under confirm_quit, until
+	// quitID's timer fires or another key is pressed.
+	quitArmed bool
+	quitID    int
+
+	// compact is the mini layout: just now playing and the status bar.
+	compact bool
+
+	// navCols and queueCols are panel widths set in the config or from the
+	// keyboard, overriding the automatic ones (0 = automatic).
+	navCols   int
+	queueCols int
 
 	// Layout.
-	width  int
-	height int
-	ready  bool
+	width   int
+	height  int
+	ready   bool
+	blurred bool // terminal lost focus; UI ticks pause, audio keeps playing
 }
 
-func New(cfg config.Config, database *db.DB, client *subsonic.Client, p *player.Player) Model {
+func New(cfg config.Config, database *db.DB, client *subsonic.Client, p *player.Player, ctl *control.Server) Model {
 	theme := ui.LoadTheme(cfg.Theme)
 	styles := ui.NewStyles(theme)
 
+	albumArt := ui.NewAlbumArt(8, cfg.UI.AlbumArt)
+	slog.Info("album art backend",
+		"configured", cfg.UI.AlbumArt, "detected", albumArt.Detected(), "selected", albumArt.Backend())
+	albumArt.SetRounded(cfg.UI.ArtBorder)
+
+	nowPlaying := ui.NewNowPlayingPanel(&styles)
+	nowPlaying.SetArtBorder(cfg.UI.ArtBorder)
+	nowPlaying.SetArtPosition(ui.ArtPosition(cfg.UI.ArtPosition))
+	nowPlaying.SetDense(cfg.UI.Dense)
+	nowPlaying.SetElapsedOnly(cfg.UI.ElapsedOnly)
+	bar, err := ui.NewProgressBar(cfg.UI.ProgressBar, cfg.UI.ProgressFilled, cfg.UI.ProgressEmpty)
+	if err != nil {
+		slog.Warn("using the default seek bar", "err", err)
+	}
+	nowPlaying.SetProgressBar(bar)
+	if cfg.UI.ProgressGradient {
+		nowPlaying.SetGradient(ui.NewGradient(theme))
+	}
+	if cfg.UI.Visualizer && p != nil {
+		p.EnableLevels()
+	}
+	info := ui.NewInfo(&styles)
+	info.SetClock(ui.ClockLayout(cfg.UI.Clock))
+	var backdrop *ui.Backdrop
+	if cfg.UI.Background {
+		backdrop = ui.NewBackdrop(theme.BgDim)
+	}
+	links := newLinker(cfg.UI.Hyperlinks, client)
+	nowPlaying.SetLinker(links)
+
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	s.Style = lipgloss.NewStyle().Foreground(theme.Accent)
 
+	palette := ui.NewPalette(database, &styles, cfg.UI.SearchMinChars)
+	palette.SetSections(cfg.UI.PaletteSections)
+	palette.SetLimit(cfg.UI.SearchLimit)
+	palette.SetKinds(cfg.UI.SearchKinds)
+	palette.SetDense(cfg.UI.Dense)
+
+	startFocus := focusNamed(cfg.UI.StartFocus)
+	queue := newQueue(cfg, &styles)
+	queue.SetFocused(startFocus == focusQueue)
+
 	return Model{
 		cfg:        cfg,
 		db:         database,
 		client:     client,
 		spinner:    s,
 		player:     p,
+		control:    ctl,
 		styles:     styles,
-		queue:      ui.NewQueue(&styles),
-		nowPlaying: ui.NewNowPlayingPanel(&styles),
-		albumArt:   ui.NewAlbumArt(8),
-		palette:    ui.NewPalette(database, &styles),
+		queue:      queue,
+		nowPlaying: nowPlaying,
+		albumArt:   albumArt,
+		artMisses:  make(map[string]time.Time),
+		links:      links,
+		albumSort:  db.AlbumSort(cfg.UI.AlbumSort),
+		topSongs:   make(map[string][]ui.QueueTrack),
+		palette:    palette,
+		info:       info,
+		picker:     ui.NewPicker(&styles),
 		syncing:    client != nil,
-		focus:      focusContent,
+		focus:      startFocus,
+		remaining:  cfg.UI.TimeRemaining,
+		navCols:    cfg.UI.NavWidth,
+		queueCols:  cfg.UI.QueueWidth,
+
+		continueArtist: cfg.Playback.ContinueArtist,
+
+		backdrop: backdrop,
 	}
 }
 
+func newQueue(cfg config.Config, styles *ui.Styles) *ui.Queue {
+	q := ui.NewQueue(styles)
+	q.SetMaxLen(cfg.Playback.MaxQueue)
+	q.SetFollow(ui.FollowMode(cfg.UI.QueueFollow), time.Duration(cfg.UI.QueueFollowIdleSec)*time.Second)
+	return q
+}
+
 func (m Model) Init() tea.Cmd {
 	if m.client != nil {
-		return tea.Batch(m.spinner.Tick, m.runSync)
+		return tea.Batch(m.spinner.Tick, m.runSync, m.probeCapabilities, sizeCheck())
 	}
-	return func() tea.Msg {
+	return tea.Batch(func() tea.Msg {
 		return syncDoneMsg{result: &subsonic.SyncResult{}}
-	}
+	}, sizeCheck())
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	mm, ok := model.(Model)
+	if !ok {
+		return model, cmd
+	}
+	if _, ok := msg.(frameMsg); ok {
+		// Frames only redraw; they leave the player alone.
+		return mm, cmd
+	}
+	mm.publishStatus()
+	mm.exportNowPlaying()
+	mm.countListened()
+	if c, ok := mm.queue.TakeCap(); ok {
+		note := mm.text(msgQueueTrimmed, c.Dropped, c.Limit)
+		if c.Over {
+			note = mm.text(msgQueueOver, c.Limit)
+		}
+		cmd = tea.Batch(cmd, mm.flashOSD(note))
+	}
+	if !mm.marqueeOn && mm.nav != nil && mm.nav.NeedsMarquee() {
+		mm.marqueeOn = true
+		cmd = tea.Batch(cmd, marqueeTick())
+	}
+	return mm, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case control.CommandMsg:
+		return m, m.handleCommand(msg.Action)
+
 	case tea.KeyMsg:
 		// Command palette captures all input when open.
 		if m.palette.IsOpen() {
 			return m.updatePalette(msg)
 		}
+		if m.picker.IsOpen() {
+			return m.updatePicker(msg)
+		}
 
-		if key.Matches(msg, keys.Quit) {
-			if m.player != nil {
-				m.player.Stop()
+		// Any key dismisses the info overlay.
+		if m.info.IsOpen() {
+			m.info.Close()
+			return m, nil
+		}
+
+		// The quit prompt takes the next key: y or q again quits, anything
+		// else stays.
+		if m.quitArmed {
+			m.quitArmed = false
+			m.osd = ""
+			if msg.String() == "y" || key.Matches(msg, keys.Quit) {
+				return m, m.quit()
 			}
-			if m.albumArt.Supported() {
-				m.albumArt.ClearAll()
+			return m, nil
+		}
+
+		// Accumulate a numeric count prefix; any other key consumes it.
+		if d := msg.String(); len(d) == 1 && d[0] >= '0' && d[0] <= '9' && !m.syncing {
+			if d != "0" || m.count > 0 {
+				m.count = min(m.count*10+int(d[0]-'0'), 9999)
+				return m, nil
+			}
+		}
+		count := max(m.count, 1)
+		m.count = 0
+
+		// With confirm_quit the first quit press arms, the second (or y) quits.
+		if key.Matches(msg, keys.Quit) {
+			if m.cfg.UI.ConfirmQuit {
+				return m, m.askQuit()
 			}
-			return m, tea.Quit
+			return m, m.quit()
+		}
+
+		if key.Matches(msg, keys.Compact) {
+			return m, m.toggleCompact()
+		}
+		// The mini layout only takes playback controls.
+		if m.compact && !key.Matches(msg, compactKeys...) {
+			return m, nil
 		}
 
 		if key.Matches(msg, keys.Palette) && !m.syncing {
@@ -128,9 +357,118 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		if key.Matches(msg, keys.Info) && !m.syncing {
+			m.info.SetSize(m.width, m.contentHeight())
+			m.info.Open(m.libraryStats())
+			return m, nil
+		}
+
+		if key.Matches(msg, keys.Pause) && m.stopped && m.queue.Current() != nil {
+			return m, m.playQueueTrack(m.queue.Current())
+		}
+
 		if key.Matches(msg, keys.Pause) && m.player != nil && m.queue.Current() != nil {
-			m.player.TogglePause()
-			m.paused = !m.paused
+			return m, m.togglePause()
+		}
+
+		if key.Matches(msg, keys.Pause) && m.cfg.UI.Space == "smart" && !m.syncing {
+			return m.playSelected()
+		}
+
+		if key.Matches(msg, keys.Stop) && m.player != nil && m.queue.Current() != nil && !m.stopped {
+			return m, m.stop()
+		}
+
+		if key.Matches(msg, keys.VolumeUp, keys.VolumeDown) && m.player != nil {
+			step := volumeStep * count
+			if key.Matches(msg, keys.VolumeDown) {
+				step = -step
+			}
+			level := m.player.SetVolume(m.player.Volume() + step)
+			return m, m.flashOSD(m.text(msgVolume, level))
+		}
+
+		if key.Matches(msg, keys.Mute) && m.player != nil {
+			if m.player.ToggleMute() {
+				return m, m.flashOSD(m.text(msgMuted))
+			}
+			return m, m.flashOSD(m.text(msgVolume, m.player.Volume()))
+		}
+
+		if key.Matches(msg, keys.SeekBack, keys.SeekFwd, keys.SeekBackLarge, keys.SeekFwdLarge) &&
+			m.player != nil && m.queue.Current() != nil {
+			if !m.seekable && !m.stopped {
+				return m, m.flashOSD(m.text(msgCantSeek))
+			}
+			step := m.cfg.Playback.SeekStep.Duration
+			if key.Matches(msg, keys.SeekBackLarge, keys.SeekFwdLarge) {
+				step = m.cfg.Playback.SeekStepLarge.Duration
+			}
+			delta := step * time.Duration(count)
+			if key.Matches(msg, keys.SeekBack, keys.SeekBackLarge) {
+				delta = -delta
+			}
+			pos, err := m.player.Seek(delta)
+			if err != nil {
+				return m, m.flashOSD(m.seekError(err))
+			}
+			return m, m.flashOSD(m.text(msgSeek, formatDuration(int(pos.Milliseconds()))))
+		}
+
+		if key.Matches(msg, keys.Restart) && m.player != nil && m.queue.Current() != nil {
+			return m, m.restart()
+		}
+
+		if key.Matches(msg, keys.RefreshArt) {
+			return m, m.refreshArt()
+		}
+
+		if key.Matches(msg, keys.SkipNext, keys.SkipPrev) && m.player != nil {
+			n := count
+			if key.Matches(msg, keys.SkipPrev) {
+				n = -n
+			}
+			if track := m.queue.Skip(n); track != nil {
+				return m, m.playQueueTrack(track)
+			}
+			return m, nil
+		}
+
+		if key.Matches(msg, keys.Playlists) && !m.syncing {
+			return m, m.fetchPlaylists(pickBrowse)
+		}
+
+		if key.Matches(msg, keys.Radio) && !m.syncing {
+			return m, m.toggleRadio()
+		}
+
+		if key.Matches(msg, keys.Continue) {
+			return m, m.toggleContinueArtist()
+		}
+
+		if key.Matches(msg, keys.Bookmark) && !m.syncing {
+			return m, m.addBookmark("")
+		}
+
+		if key.Matches(msg, keys.Bookmarks) && !m.syncing {
+			return m, m.openBookmarks()
+		}
+
+		// alt+h/l move the nav's divider, alt+H/L the queue's.
+		if key.Matches(msg, keys.NavNarrower, keys.NavWider, keys.QueueNarrower, keys.QueueWider) && !m.syncing {
+			delta := resizeStep
+			if key.Matches(msg, keys.NavNarrower, keys.QueueWider) {
+				delta = -delta
+			}
+			return m, m.nudgePanel(key.Matches(msg, keys.QueueNarrower, keys.QueueWider), delta)
+		}
+
+		if key.Matches(msg, keys.ResetPanels) && !m.syncing {
+			return m, m.resetPanels()
+		}
+
+		if key.Matches(msg, keys.TimeMode) {
+			m.toggleRemaining()
 			return m, nil
 		}
 
@@ -144,11 +482,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if len(tracks) > 0 {
 				rand.Shuffle(len(tracks), func(i, j int) { tracks[i], tracks[j] = tracks[j], tracks[i] })
 				m.replaceQueue(tracks, 0)
-				return m, m.playQueueTrack(m.queue.Current())
+				return m, tea.Batch(m.playQueueTrack(m.queue.Current()), m.flashOSD(m.text(msgShuffled)))
 			}
 			return m, nil
 		}
 
+		if key.Matches(msg, keys.ShuffleQueue) && m.queue.Len() > 0 {
+			if m.queue.ToggleShuffle() {
+				return m, m.flashOSD(m.text(msgQueueShuffled))
+			}
+			return m, m.flashOSD(m.text(msgQueueRestored))
+		}
+
 		if !m.syncing {
 			switch m.focus {
 			case focusArtistNav:
@@ -161,6 +506,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.MouseMsg:
+		if m.compact {
+			return m, nil
+		}
 		return m.handleMouse(msg)
 
 	case tea.WindowSizeMsg:
@@ -169,6 +517,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.ready = true
 		m.resizePanels()
 		m.palette.SetSize(m.width, m.contentHeight())
+		m.info.SetSize(m.width, m.contentHeight())
+		m.picker.SetSize(m.width, m.contentHeight())
+
+	case sizeCheckMsg:
+		if !m.ready {
+			return m.update(terminalSize())
+		}
 
 	case spinner.TickMsg:
 		if m.syncing {
@@ -177,69 +532,214 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
+	case marqueeMsg:
+		if m.nav != nil && m.nav.NeedsMarquee() {
+			m.nav.AdvanceMarquee()
+			return m, marqueeTick()
+		}
+		m.marqueeOn = false
+
 	case tickMsg:
-		if m.queue.Current() != nil {
-			return m, tickCmd()
+		if msg.id == m.tickID && m.queue.Current() != nil && !m.paused && !m.stopped && !m.blurred {
+			if m.cfg.UI.Visualizer && m.player != nil {
+				m.levels = m.player.Levels()
+			}
+			m.saveEpisodePosition(false)
+			return m, m.tickCmd()
+		}
+
+	case frameMsg:
+		if msg.id == m.tickID && m.queue.Current() != nil && !m.paused && !m.stopped && !m.blurred {
+			return m, m.frameCmd()
+		}
+
+	case playlistsMsg:
+		return m, m.openPlaylistPicker(msg)
+
+	case playlistMsg:
+		return m, m.openPlaylistEntries(msg)
+
+	case playlistUpdatedMsg:
+		return m, m.handlePlaylistUpdated(msg)
+
+	case topSongsMsg:
+		return m, m.handleTopSongs(msg)
+
+	case radioTracksMsg:
+		return m, m.handleRadioTracks(msg)
+
+	case osdClearMsg:
+		if msg.id == m.osdID {
+			m.osd = ""
+		}
+
+	case quitDisarmMsg:
+		if msg.id == m.quitID {
+			m.quitArmed = false
+		}
+		if msg.osdID == m.osdID {
+			m.osd = ""
+		}
+
+	case tea.BlurMsg:
+		m.blurred = true
+
+	case tea.FocusMsg:
+		m.blurred = false
+		if m.queue.Current() != nil && !m.paused && !m.stopped {
+			return m, m.restartTick()
 		}
 
 	case syncDoneMsg:
 		m.syncing = false
+		clear(m.artMisses) // a sync may have brought in new art
 		if msg.result.Tracks > 0 {
-			m.syncMsg = fmt.Sprintf("%d artists · %d albums · %d tracks %s",
-				msg.result.Artists, msg.result.Albums, msg.result.Tracks,
-				m.styles.AppDim.Render("("+msg.result.Elapsed.Round(time.Millisecond).String()+")"))
+			m.syncMsg = m.text(msgSynced, msg.result.Artists, msg.result.Albums, msg.result.Tracks) + " " +
+				m.styles.AppDim.Render("("+msg.result.Elapsed.Round(time.Millisecond).String()+")")
 		}
 		m.nav = ui.NewArtistNav(m.db, &m.styles)
 		m.nav.SetFocused(m.focus == focusArtistNav)
-		m.content = ui.NewContentBrowser(m.db, &m.styles)
+		m.content = ui.NewContentBrowser(m.db, &m.styles, m.cfg.UI.ArtistSeparators)
+		m.content.SetLinker(m.links)
 		m.content.SetFocused(m.focus == focusContent)
 		m.resizePanels()
+		m.applyStartup()
 
 	case syncErrMsg:
 		m.syncing = false
 		m.syncErr = msg.Error()
 		m.nav = ui.NewArtistNav(m.db, &m.styles)
-		m.content = ui.NewContentBrowser(m.db, &m.styles)
+		m.content = ui.NewContentBrowser(m.db, &m.styles, m.cfg.UI.ArtistSeparators)
+		m.content.SetLinker(m.links)
 		m.resizePanels()
+		m.applyStartup()
 
 	case playStartedMsg:
+		m.playGen = msg.gen
+		m.seekable = m.player.Seekable()
 		m.paused = false
+		m.stopped = false
 		m.playErr = ""
-		if m.client != nil {
-			if cur := m.queue.Current(); cur != nil {
+		// Play started at any stopped position; it's used up now.
+		m.resumeID, m.resumeAt = "", 0
+		m.loadBookmarks()
+		if cur := m.queue.Current(); cur != nil {
+			if cur.ID != m.transcodeID {
+				m.transcodeID = ""
+			}
+			m.rememberPlayed(cur.ID)
+			if m.client != nil {
 				go m.client.NowPlaying(cur.ID)
 			}
 		}
+		if m.radio {
+			m.queue.DropPlayed()
+			m.resizePanels()
+		}
 		var artCmd tea.Cmd
-		if cur := m.queue.Current(); cur != nil && cur.AlbumID != m.artAlbumID {
-			artCmd = m.fetchCoverArt(cur.AlbumID)
+		if cur := m.queue.Current(); cur != nil && artKey(cur) != m.artAlbumID && !m.artMissed(artKey(cur)) {
+			artCmd = m.fetchCoverArt(cur)
 		}
-		return m, tea.Batch(m.waitForTrackEnd, tickCmd(), artCmd)
+		return m, tea.Batch(m.waitForTrackEnd, m.restartTick(), artCmd, m.radioTopUp())
 
 	case coverArtMsg:
 		m.artData = msg.data
 		m.artAlbumID = msg.albumID
+		switch {
+		case msg.missing:
+			m.artMisses[msg.albumID] = time.Now()
+		case msg.err != nil:
+			// Likely transient: forget the attempt so the next track
+			// change, or a refresh, tries again.
+			m.artAlbumID = ""
+		}
 
 	case playErrMsg:
 		m.playErr = msg.Error()
 
 	case trackEndedMsg:
-		if m.client != nil {
-			if cur := m.queue.Current(); cur != nil {
+		// Ignore end signals from tracks that have since been replaced.
+		if msg.gen != m.playGen {
+			return m, nil
+		}
+		if msg.reason == endTruncated {
+			slog.Warn("stream ended early", "elapsed", msg.elapsed)
+			cur := m.queue.Current()
+			if cur == nil {
+				return m, nil
+			}
+			at := time.Duration(msg.elapsed * float64(time.Second))
+			// A file that stopped decoding part way may still play as the
+			// server transcodes it, from where it stopped.
+			if errors.Is(msg.err, player.ErrDecode) && cur.ID != m.transcodeID && m.cfg.Playback.FallbackFormat != "" {
+				slog.Warn("decoding failed part way, retrying transcoded", "track", cur.ID, "err", msg.err)
+				m.transcodeID = cur.ID
+				m.resumeID, m.resumeAt = cur.ID, at
+				return m, m.playQueueTrack(cur)
+			}
+			// Retry once, picking up where the stream was cut off.
+			if cur.ID != m.retriedID {
+				m.retriedID = cur.ID
+				m.resumeID, m.resumeAt = cur.ID, at
+				return m, m.playQueueTrack(cur)
+			}
+			// Cut off again: say so and move on rather than stall here.
+			m.retriedID = ""
+			text := m.text(msgEndedEarly, formatDuration(int(msg.elapsed*1000)))
+			cmd := m.advance(*cur)
+			if cmd == nil {
+				m.playErr = text
+				return m, nil
+			}
+			return m, tea.Batch(m.flashOSD(text), cmd)
+		}
+		m.retriedID = ""
+		m.countListened()
+		m.saveListened()
+		if cur := m.queue.Current(); cur != nil {
+			if m.client != nil {
 				go m.client.Scrobble(cur.ID)
 			}
+			if err := m.db.CountPlay(cur.ID); err != nil {
+				slog.Warn("counting play failed", "track", cur.ID, "err", err)
+			}
+			if m.content != nil {
+				m.content.MarkPlayed(cur.ID)
+			}
 		}
-		next := m.queue.Next()
-		if next != nil {
-			return m, m.playQueueTrack(next)
+		var last ui.QueueTrack
+		if cur := m.queue.Current(); cur != nil {
+			last = *cur
 		}
-		m.paused = false
-		m.resizePanels()
+		if last.Podcast {
+			// Finished: the next play starts from the top.
+			if err := m.db.SetEpisodePosition(last.ID, 0); err != nil {
+				slog.Warn("clearing episode position failed", "episode", last.ID, "err", err)
+			}
+		}
+		cmd := m.advance(last)
+		return m, cmd
 	}
 
 	return m, nil
 }
 
+// advance moves on from the track that just ended, last, to the next in the
+// queue or, past the end, the artist's next album. It returns nil when
+// there's nothing left to play.
+func (m *Model) advance(last ui.QueueTrack) tea.Cmd {
+	next := m.queue.Next()
+	if next == nil && last.ID != "" {
+		next = m.nextArtistAlbum(last)
+	}
+	if next != nil {
+		return m.playQueueTrack(next)
+	}
+	m.paused = false
+	m.resizePanels()
+	return nil
+}
+
 // --- Mouse handling ---
 
 func (m *Model) handleMouse(msg tea.MouseMsg) (Model, tea.Cmd) {
@@ -294,6 +794,16 @@ func (m *Model) handleMouseClick(x, y int) (Model, tea.Cmd) {
 
 	navWidth, contentWidth, _ := m.tripleWidths()
 
+	// Now playing sits under the panels; clicking the total time at the
+	// right end of its seek bar toggles it to time remaining. With
+	// elapsed_only the bar runs to the end instead.
+	seekRow := m.nowPlaying.SeekRow()
+	if end := m.width - m.nowPlaying.RightInset(); seekRow >= 0 && y == contentBottom+seekRow && x >= end-10 && x < end &&
+		!m.cfg.UI.ElapsedOnly && m.queue.Current() != nil {
+		m.toggleRemaining()
+		return *m, nil
+	}
+
 	if y < contentTop || y >= contentBottom {
 		return *m, nil
 	}
@@ -360,6 +870,23 @@ func (m *Model) updatePalette(msg tea.KeyMsg) (Model, tea.Cmd) {
 		m.palette.Close()
 		return m.handlePaletteSelect(sel)
 
+	case tea.KeyTab:
+		// Tab plays every matching track.
+		tracks, err := m.db.TracksByID(m.palette.TrackIDs(playAllLimit))
+		if err != nil || len(tracks) == 0 {
+			return *m, nil
+		}
+		m.palette.Close()
+		m.replaceQueue(tracks, 0)
+		return *m, tea.Batch(m.playQueueTrack(m.queue.Current()),
+			m.flashOSD(m.text(msgPlayingResults, len(tracks))))
+
+	case tea.KeyCtrlT:
+		if m.palette.ToggleTracksOnly() {
+			return *m, m.flashOSD(m.text(msgTracksOnly))
+		}
+		return *m, m.flashOSD(m.text(msgAllResults))
+
 	case tea.KeyUp, tea.KeyCtrlK:
 		m.palette.CursorUp()
 		return *m, nil
@@ -405,23 +932,25 @@ func (m *Model) handlePaletteSelect(sel *ui.PaletteResult) (Model, tea.Cmd) {
 		return *m, nil
 
 	case "album":
-		// Filter to artist, scroll to album.
+		// Filter to the album's artist, scroll to album.
+		artistID := m.browserArtist(sel.AlbumID, sel.ArtistID)
 		if m.nav != nil {
-			m.nav.SelectByID(sel.ArtistID)
+			m.nav.SelectByID(artistID)
 		}
 		if m.content != nil {
-			m.content.FilterByArtist(sel.ArtistID)
+			m.content.FilterByArtist(artistID)
 			m.content.ScrollToAlbum(sel.AlbumID)
 		}
 		return *m, nil
 
 	case "track":
-		// Filter to artist, scroll to track, and play.
+		// Filter to the album's artist, scroll to track, and play.
+		artistID := m.browserArtist(sel.AlbumID, sel.ArtistID)
 		if m.nav != nil {
-			m.nav.SelectByID(sel.ArtistID)
+			m.nav.SelectByID(artistID)
 		}
 		if m.content != nil {
-			m.content.FilterByArtist(sel.ArtistID)
+			m.content.FilterByArtist(artistID)
 			m.content.ScrollToTrack(sel.ID)
 		}
 		// Queue album from this track onward.
@@ -438,6 +967,27 @@ func (m *Model) handlePaletteSelect(sel *ui.PaletteResult) (Model, tea.Cmd) {
 		}
 		m.replaceQueue(tracks, startIdx)
 		return *m, m.playQueueTrack(m.queue.Current())
+
+	case "decade":
+		return *m, m.openDecade(sel.Year)
+
+	case "command":
+		switch sel.ID {
+		case "duplicates":
+			return *m, m.openDuplicates()
+		case "save":
+			return *m, m.saveSnapshot(sel.Arg)
+		case "snapshots":
+			return *m, m.openSnapshots()
+		case "bookmark":
+			return *m, m.addBookmark(sel.Arg)
+		case "bookmarks":
+			return *m, m.openBookmarks()
+		case "podcasts":
+			return *m, m.openPodcasts()
+		case "episodes":
+			return *m, m.openNewestEpisodes()
+		}
 	}
 
 	return *m, nil
@@ -469,6 +1019,10 @@ func (m *Model) updateArtistNav(msg tea.KeyMsg) (Model, tea.Cmd) {
 		m.nav.MoveTop()
 	case key.Matches(msg, keys.Bottom):
 		m.nav.MoveBottom()
+	case key.Matches(msg, keys.ScreenTop):
+		m.nav.MoveScreenTop()
+	case key.Matches(msg, keys.ScreenMiddle):
+		m.nav.MoveScreenMiddle()
 	case key.Matches(msg, keys.HalfDown):
 		m.nav.HalfPageDown()
 	case key.Matches(msg, keys.HalfUp):
@@ -490,10 +1044,49 @@ func (m *Model) updateContent(msg tea.KeyMsg) (Model, tea.Cmd) {
 		m.content.MoveDown()
 	case key.Matches(msg, keys.Toggle):
 		return m.handleContentEnter()
+	case key.Matches(msg, keys.PlayOnward):
+		return *m, m.playArtistOnward()
+	case key.Matches(msg, keys.Enqueue):
+		if row := m.content.CursorRow(); row != nil {
+			m.enqueue(m.rowTracks(row))
+		}
+	case key.Matches(msg, keys.AlbumsView):
+		return *m, m.toggleAlbumsView()
+	case key.Matches(msg, keys.AlbumSort):
+		return *m, m.cycleAlbumSort()
+	case key.Matches(msg, keys.Unplayed):
+		if m.content.ToggleUnplayed() {
+			return *m, m.flashOSD(m.text(msgUnplayedOnly))
+		}
+		return *m, m.flashOSD(m.text(msgAllTracks))
+	case key.Matches(msg, keys.TopSongs):
+		if row := m.content.CursorRow(); row != nil {
+			return *m, m.playTopSongs(row.ArtistID, row.ArtistName)
+		}
+	case key.Matches(msg, keys.ToggleFilter):
+		if artistID := m.content.ToggleFilter(); artistID != "" {
+			if m.nav != nil {
+				m.nav.SelectByID(artistID)
+			}
+		} else if m.nav != nil {
+			m.nav.ClearFilter()
+		}
+	case key.Matches(msg, keys.AddToPlaylist):
+		if row := m.content.CursorRow(); row != nil {
+			var ids []string
+			for _, t := range m.rowTracks(row) {
+				ids = append(ids, t.ID)
+			}
+			return *m, m.startAddToPlaylist(ids)
+		}
 	case key.Matches(msg, keys.Top):
 		m.content.MoveTop()
 	case key.Matches(msg, keys.Bottom):
 		m.content.MoveBottom()
+	case key.Matches(msg, keys.ScreenTop):
+		m.content.MoveScreenTop()
+	case key.Matches(msg, keys.ScreenMiddle):
+		m.content.MoveScreenMiddle()
 	case key.Matches(msg, keys.HalfDown):
 		m.content.HalfPageDown()
 	case key.Matches(msg, keys.HalfUp):
@@ -503,6 +1096,38 @@ func (m *Model) updateContent(msg tea.KeyMsg) (Model, tea.Cmd) {
 	return *m, nil
 }
 
+// playSelected starts playback from whatever the focused panel's cursor is on.
+func (m *Model) playSelected() (Model, tea.Cmd) {
+	switch m.focus {
+	case focusArtistNav:
+		if m.nav == nil {
+			return *m, nil
+		}
+		artistID := m.nav.Select()
+		if artistID == "" {
+			return *m, nil
+		}
+		if m.content != nil {
+			m.content.FilterByArtist(artistID)
+		}
+		tracks, err := m.db.TracksForArtist(artistID)
+		if err != nil || len(tracks) == 0 {
+			return *m, nil
+		}
+		m.replaceQueue(tracks, 0)
+		return *m, m.playQueueTrack(m.queue.Current())
+	case focusContent:
+		if m.content != nil {
+			return m.handleContentEnter()
+		}
+	case focusQueue:
+		if track := m.queue.JumpTo(); track != nil {
+			return *m, m.playQueueTrack(track)
+		}
+	}
+	return *m, nil
+}
+
 func (m *Model) handleContentEnter() (Model, tea.Cmd) {
 	row := m.content.CursorRow()
 	if row == nil {
@@ -515,16 +1140,14 @@ func (m *Model) handleContentEnter() (Model, tea.Cmd) {
 		if err != nil || len(tracks) == 0 {
 			return *m, nil
 		}
-		m.replaceQueue(tracks, 0)
-		return *m, m.playQueueTrack(m.queue.Current())
+		return *m, m.selectTracks(tracks, 0, row.ArtistID)
 
 	case ui.ContentAlbum:
 		tracks, err := m.db.TracksForAlbum(row.AlbumID)
 		if err != nil || len(tracks) == 0 {
 			return *m, nil
 		}
-		m.replaceQueue(tracks, 0)
-		return *m, m.playQueueTrack(m.queue.Current())
+		return *m, m.selectTracks(tracks, 0, row.ArtistID)
 
 	case ui.ContentTrack:
 		tracks, err := m.db.TracksForAlbum(row.AlbumID)
@@ -538,13 +1161,58 @@ func (m *Model) handleContentEnter() (Model, tea.Cmd) {
 				break
 			}
 		}
-		m.replaceQueue(tracks, startIdx)
-		return *m, m.playQueueTrack(m.queue.Current())
+		return *m, m.selectTracks(tracks, startIdx, row.ArtistID)
 	}
 
 	return *m, nil
 }
 
+// playArtistOnward plays the artist's discography from the row under the
+// cursor: from a track or the start of an album through the rest of the
+// artist's albums. Enter stops at the end of the album, a appends just the
+// one track.
+func (m *Model) playArtistOnward() tea.Cmd {
+	row := m.content.CursorRow()
+	if row == nil || row.ArtistID == "" {
+		return nil
+	}
+	tracks, err := m.db.TracksForArtist(row.ArtistID)
+	if err != nil || len(tracks) == 0 {
+		return nil
+	}
+	start := 0
+	for i, t := range tracks {
+		if (row.Kind == ui.ContentTrack && t.ID == row.TrackID) ||
+			(row.Kind == ui.ContentAlbum && t.AlbumID == row.AlbumID) {
+			start = i
+			break
+		}
+	}
+	return m.selectTracks(tracks, start, row.ArtistID)
+}
+
+// selectTracks plays tracks chosen in the browser from startIdx, replacing
+// the queue or appending to it as the on_select policy says.
+func (m *Model) selectTracks(tracks []db.TrackRow, startIdx int, artistID string) tea.Cmd {
+	appendTo := false
+	if m.queue.Len() > 0 {
+		switch m.cfg.Playback.OnSelect {
+		case "append":
+			appendTo = true
+		case "artist":
+			appendTo = artistID != m.queueArtist
+		}
+	}
+	if !appendTo {
+		m.replaceQueue(tracks, startIdx)
+		m.queueArtist = artistID
+		return m.playQueueTrack(m.queue.Current())
+	}
+	m.queueArtist = artistID
+	m.enqueue(tracks)
+	return m.playQueueTrack(m.queue.PlayAt(m.queue.Len() - len(tracks) + startIdx))
+}
+
 func (m *Model) updateQueue(msg tea.KeyMsg) (Model, tea.Cmd) {
 	switch {
 	case key.Matches(msg, keys.Up):
@@ -571,25 +1239,86 @@ func (m *Model) updateQueue(msg tea.KeyMsg) (Model, tea.Cmd) {
 		m.queue.MoveUp()
 	case key.Matches(msg, keys.MoveDown):
 		m.queue.MoveDown()
+	case key.Matches(msg, keys.Follow):
+		if m.queue.ToggleFollow() {
+			return *m, m.flashOSD(m.text(msgFollowOn))
+		}
+		return *m, m.flashOSD(m.text(msgFollowOff))
+	case key.Matches(msg, keys.GoAlbum), key.Matches(msg, keys.GoArtist):
+		if t := m.queue.Selected(); t != nil {
+			m.revealInBrowser(t, key.Matches(msg, keys.GoAlbum))
+		}
+	case key.Matches(msg, keys.TopSongs):
+		if t := m.queue.Selected(); t != nil {
+			return *m, m.playTopSongs(t.ArtistID, t.Artist)
+		}
+	case key.Matches(msg, keys.AddToPlaylist):
+		if t := m.queue.Selected(); t != nil {
+			return *m, m.startAddToPlaylist([]string{t.ID})
+		}
 	}
 
 	return *m, nil
 }
 
+// revealInBrowser filters the browser to a queue track's artist and focuses
+// it, scrolled to the track's album (or the artist header).
+func (m *Model) revealInBrowser(t *ui.QueueTrack, toAlbum bool) {
+	if m.content == nil || t.ArtistID == "" {
+		return
+	}
+	// An album shows under its own artist, which for a compilation isn't
+	// the track's.
+	artistID := t.ArtistID
+	if toAlbum {
+		artistID = m.browserArtist(t.AlbumID, t.ArtistID)
+	}
+	if m.nav != nil {
+		m.nav.SelectByID(artistID)
+	}
+	m.content.FilterByArtist(artistID)
+	if toAlbum {
+		m.content.ScrollToAlbum(t.AlbumID)
+	} else {
+		m.content.ScrollToArtist(artistID)
+	}
+	m.setFocus(focusContent)
+}
+
 // --- View ---
 
 func (m Model) View() string {
 	if !m.ready {
 		return ""
 	}
+	if m.compact {
+		return m.viewCompact()
+	}
+	if m.width < minWidth || m.height < minHeight {
+		return m.viewTooSmall()
+	}
 
-	header := m.styles.Header.Width(m.width).Render("🦊 kitsune")
+	title := "🦊 kitsune"
+	if m.client != nil {
+		title += "  " + m.styles.AppDim.Render(m.client.Server().String())
+	}
+	if m.radio {
+		title += "  📻 radio"
+	}
+	if m.continueArtist {
+		title += "  ⤵ continue artist"
+	}
+	header := m.styles.Header.Width(m.width).Render(title)
 
 	var content string
 	if m.palette.IsOpen() {
 		content = m.palette.View()
+	} else if m.info.IsOpen() {
+		content = m.info.View()
+	} else if m.picker.IsOpen() {
+		content = m.picker.View()
 	} else if m.syncing {
-		inner := m.spinner.View() + " syncing library..."
+		inner := m.spinner.View() + " " + m.text(msgSyncing)
 		content = lipgloss.NewStyle().
 			Height(m.contentHeight()).
 			Padding(1, 2).
@@ -598,49 +1327,235 @@ func (m Model) View() string {
 		content = m.renderTriplePanels()
 	}
 
-	// Now playing section.
+	parts := []string{header, content}
+	if nowPlaying := m.nowPlayingView(); nowPlaying != "" {
+		parts = append(parts, nowPlaying)
+	}
+	parts = append(parts, m.statusView())
+
+	return m.backdrop.Paint(lipgloss.JoinVertical(lipgloss.Left, parts...), m.width, m.height)
+}
+
+// viewCompact is the mini layout: the now playing panel and the status bar.
+func (m Model) viewCompact() string {
+	view := m.statusView()
+	if nowPlaying := m.nowPlayingView(); nowPlaying != "" {
+		view = lipgloss.JoinVertical(lipgloss.Left, nowPlaying, view)
+	}
+	return m.backdrop.Paint(view, m.width, m.height)
+}
+
+// shownElapsed is the playback position to draw. Between ticks it moves
+// the last reading on by the wall time since, so frames don't each take the
+// player's lock; the next reading corrects any drift. Pauses and seeks are
+// read straight away, as every key press takes a fresh reading.
+func (m Model) shownElapsed() float64 {
+	if m.player == nil {
+		return 0
+	}
+	if m.listenAt.IsZero() || m.listenGen != m.playGen {
+		return m.player.Elapsed()
+	}
+	if m.paused || m.stopped {
+		return m.listenPos
+	}
+	tick := time.Duration(m.cfg.UI.TickMs) * time.Millisecond
+	return m.listenPos + min(time.Since(m.listenAt), tick).Seconds()
+}
+
+// nowPlayingView renders the now playing panel, or "" when the queue is empty.
+func (m Model) nowPlayingView() string {
 	var nowPlaying string
 	if cur := m.queue.Current(); cur != nil {
-		elapsed := 0.0
-		if m.player != nil {
-			elapsed = m.player.Elapsed()
+		elapsed := m.shownElapsed()
+
+		artReady := len(m.artData) > 0 && m.artAlbumID == artKey(cur)
+		hasArt := m.albumArt.Supported() && artReady
+
+		// Without a graphics protocol, fall back to character art.
+		var art string
+		if artReady && m.albumArt.TextArt() {
+			art = m.albumArt.RenderText(artKey(cur), m.artData, m.nowPlaying.ArtRows())
 		}
 
-		hasArt := m.albumArt.Supported() && len(m.artData) > 0 && m.artAlbumID == cur.AlbumID
+		var transcode string
+		if m.player != nil {
+			if np := m.player.Current(); np != nil && np.TrackID == cur.ID {
+				transcode = np.StreamFormat
+			}
+		}
 
 		info := ui.NowPlayingInfo{
 			Title:      cur.Title,
 			Artist:     cur.Artist,
 			Album:      cur.Album,
+			AlbumID:    cur.AlbumID,
+			ArtistID:   cur.ArtistID,
 			Year:       cur.Year,
+			Format:     cur.Format,
+			BitRate:    cur.BitRate,
+			Transcode:  transcode,
 			ElapsedSec: elapsed,
 			DurationMs: cur.DurationMs,
 			Paused:     m.paused,
+			Halted:     m.stopped,
+			Remaining:  m.remaining,
 			HasArt:     hasArt,
+			Art:        art,
+			Marks:      m.markSeconds(cur.ID),
+			NoSeek:     !m.seekable && !m.stopped,
+			Levels:     m.levels,
 		}
 
 		nowPlaying = m.nowPlaying.View(info)
+	} else if m.queue.Len() > 0 {
+		// Queue loaded but nothing playing: keep the panel in a stopped state.
+		nowPlaying = m.nowPlaying.View(ui.NowPlayingInfo{Stopped: true, Queued: m.queue.Len()})
 	}
+	return nowPlaying
+}
 
-	// Status bar.
-	hints := "j/k: move  enter: play  space: pause  s: shuffle  tab: switch  ctrl+p: search  q: quit"
-	var statusText string
+// statusView renders the status bar: errors, position and key hints, or a
+// transient message.
+func (m Model) statusView() string {
+	// The layout budgets one row for the bar, so nothing here may wrap.
+	inner := m.width - m.styles.Status.GetHorizontalFrameSize()
+	var parts []string
+	if pos := m.positionReadout(); pos != "" {
+		parts = append(parts, m.styles.NpTime.Render(pos))
+	}
 	if m.playErr != "" {
-		statusText = m.styles.Error.Render(m.playErr) + "  " + m.styles.AppDim.Render(hints)
+		parts = append(parts, m.styles.Error.Render(m.playErr))
 	} else if m.syncErr != "" {
-		statusText = m.styles.Error.Render("sync: "+m.syncErr) + "  " + m.styles.AppDim.Render(hints)
+		parts = append(parts, m.styles.Error.Render("sync: "+m.syncErr))
+	}
+	room := inner
+	for _, p := range parts {
+		room -= lipgloss.Width(p) + 2
+	}
+	if hints := m.statusHints(room); hints != "" {
+		parts = append(parts, m.styles.AppDim.Render(hints))
+	}
+	statusText := lipgloss.NewStyle().MaxWidth(inner).Render(strings.Join(parts, "  "))
+	if m.osd != "" {
+		// Same single row as the hints, so the layout doesn't shift.
+		statusText = lipgloss.PlaceHorizontal(inner, lipgloss.Center, m.styles.QueueNow.Render(m.osd))
+	}
+	return m.styles.Status.Width(m.width).Render(statusText)
+}
+
+// viewTooSmall replaces the layout when the terminal can't fit it.
+func (m Model) viewTooSmall() string {
+	msg := lipgloss.JoinVertical(lipgloss.Center,
+		m.styles.Error.Render("terminal too small"),
+		m.styles.AppDim.Render(fmt.Sprintf("need at least %d×%d, have %d×%d", minWidth, minHeight, m.width, m.height)))
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, msg)
+}
+
+// positionReadout returns "elapsed / duration" for the current track, or "".
+func (m Model) positionReadout() string {
+	cur := m.queue.Current()
+	if cur == nil || m.player == nil {
+		return ""
+	}
+	elapsedMs := int(m.shownElapsed() * 1000)
+	if m.cfg.UI.ElapsedOnly {
+		return formatDuration(elapsedMs)
+	}
+	return formatDuration(elapsedMs) + " / " + formatDuration(cur.DurationMs)
+}
+
+// startPosition is where track starts playing: where it was stopped, if
+// it's the track that was stopped, or for a podcast episode, where listening
+// left off. Anything else starts from the top.
+func (m Model) startPosition(track *ui.QueueTrack) time.Duration {
+	at := time.Duration(0)
+	switch {
+	case track.ID == m.resumeID:
+		at = m.resumeAt
+	case track.Podcast:
+		at = m.db.EpisodePosition(track.ID)
+	}
+	if at < time.Second {
+		return 0
+	}
+	return at
+}
+
+// seekError is the message shown when a seek fails.
+func (m Model) seekError(err error) string {
+	if errors.Is(err, player.ErrBuffering) {
+		return m.text(msgStillBuffering)
+	}
+	return m.text(msgCantSeek)
+}
+
+// toggleRemaining flips the seek bar between total and remaining time for
+// the rest of the session; time_remaining sets where it starts.
+func (m *Model) toggleRemaining() {
+	m.remaining = !m.remaining
+}
+
+// toggleCompact switches between the full and mini layouts. Coming back
+// to the full layout restores the panels and their focus.
+func (m *Model) toggleCompact() tea.Cmd {
+	m.compact = !m.compact
+	if m.compact {
+		m.palette.Close()
+		m.picker.Close()
+		m.info.Close()
+		return nil
+	}
+	m.resizePanels()
+	m.setFocus(m.focus)
+	return nil
+}
+
+// askQuit arms quitting and shows the prompt in the status bar until it's
+// answered or quitWindow passes. While a track is loaded the prompt asks
+// y/n; otherwise it just asks for q again.
+func (m *Model) askQuit() tea.Cmd {
+	m.quitArmed = true
+	m.quitID++
+	m.osdID++
+	if m.queue.Current() != nil && !m.stopped {
+		m.osd = m.text(msgQuitPrompt)
 	} else {
-		statusText = m.styles.AppDim.Render(hints)
+		m.osd = m.text(msgQuitAgain)
 	}
-	status := m.styles.Status.Width(m.width).Render(statusText)
+	id, osdID := m.quitID, m.osdID
+	return tea.Tick(quitWindow, func(time.Time) tea.Msg {
+		return quitDisarmMsg{id, osdID}
+	})
+}
 
-	parts := []string{header, content}
-	if nowPlaying != "" {
-		parts = append(parts, nowPlaying)
+// quit saves session state, stops playback and exits.
+func (m *Model) quit() tea.Cmd {
+	m.countListened()
+	m.saveListened()
+	m.saveUIState()
+	m.saveSession()
+	m.saveEpisodePosition(true)
+	if m.cfg.Control.NowPlayingFile != "" {
+		writeFileAtomic(m.cfg.Control.NowPlayingFile, "")
+	}
+	if m.player != nil {
+		m.player.Stop()
+	}
+	if m.albumArt.Supported() {
+		m.albumArt.ClearAll()
 	}
-	parts = append(parts, status)
+	return tea.Quit
+}
 
-	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+// flashOSD shows text in the status bar for osdDuration.
+func (m *Model) flashOSD(text string) tea.Cmd {
+	m.osdID++
+	m.osd = text
+	id := m.osdID
+	return tea.Tick(osdDuration, func(time.Time) tea.Msg {
+		return osdClearMsg{id}
+	})
 }
 
 func (m Model) renderTriplePanels() string {
@@ -678,11 +1593,24 @@ func (m Model) tripleWidths() (int, int, int) {
 	if queueWidth > 50 {
 		queueWidth = 50
 	}
+	if m.queueCols > 0 {
+		queueWidth = m.queueCols
+	}
 
 	navWidth := m.width * 20 / 100
 	if navWidth < 20 {
 		navWidth = 20
 	}
+	if m.navCols > 0 {
+		navWidth = m.navCols
+	}
+
+	// Widths set from the keyboard give way when the terminal shrinks.
+	if over := navWidth + queueWidth + 2 + minContentWidth - m.width; over > 0 && (m.navCols > 0 || m.queueCols > 0) {
+		cut := min(over, max(queueWidth-minQueueWidth, 0))
+		queueWidth -= cut
+		navWidth = max(navWidth-(over-cut), minNavWidth)
+	}
 
 	// 2 dividers.
 	contentWidth := m.width - navWidth - queueWidth - 2
@@ -695,7 +1623,7 @@ func (m Model) tripleWidths() (int, int, int) {
 
 func (m Model) contentHeight() int {
 	h := m.height - 4
-	if m.queue.Current() != nil {
+	if m.queue.Len() > 0 {
 		h -= m.nowPlaying.Height()
 	}
 	return max(1, h)
@@ -743,6 +1671,41 @@ func (m *Model) cycleFocus() {
 // --- Queue helpers ---
 
 func (m *Model) replaceQueue(tracks []db.TrackRow, startIdx int) {
+	m.setQueue(toQueueTracks(tracks), startIdx)
+}
+
+// setQueue replaces the queue with tracks, starting at startIdx. Whatever
+// artist the browser last queued is gone with it.
+func (m *Model) setQueue(tracks []ui.QueueTrack, startIdx int) {
+	m.queue.Replace(tracks, startIdx)
+	m.queueArtist = ""
+	m.resizePanels()
+}
+
+// enqueue adds tracks to the end of the queue.
+func (m *Model) enqueue(tracks []db.TrackRow) {
+	m.queue.Append(toQueueTracks(tracks)...)
+	m.resizePanels()
+}
+
+// rowTracks returns the tracks a content row stands for: an artist's
+// discography, an album, or a single track.
+func (m *Model) rowTracks(row *ui.ContentRow) []db.TrackRow {
+	switch row.Kind {
+	case ui.ContentArtist:
+		tracks, _ := m.db.TracksForArtist(row.ArtistID)
+		return tracks
+	case ui.ContentAlbum:
+		tracks, _ := m.db.TracksForAlbum(row.AlbumID)
+		return tracks
+	case ui.ContentTrack:
+		tracks, _ := m.db.TracksByID([]string{row.TrackID})
+		return tracks
+	}
+	return nil
+}
+
+func toQueueTracks(tracks []db.TrackRow) []ui.QueueTrack {
 	queueTracks := make([]ui.QueueTrack, len(tracks))
 	for i, t := range tracks {
 		queueTracks[i] = ui.QueueTrack{
@@ -751,26 +1714,65 @@ func (m *Model) replaceQueue(tracks []db.TrackRow, startIdx int) {
 			Artist:     t.Artist,
 			Album:      t.Album,
 			AlbumID:    t.AlbumID,
+			ArtistID:   t.ArtistID,
 			Year:       t.Year,
 			DurationMs: t.DurationMs,
 			Format:     t.Format,
+			BitRate:    t.BitRate,
+			CoverArt:   t.CoverArt,
 		}
 	}
-	m.queue.Replace(queueTracks, startIdx)
-	m.resizePanels()
+	return queueTracks
 }
 
 // --- Messages ---
 
 type syncDoneMsg struct{ result *subsonic.SyncResult }
 type syncErrMsg struct{ error }
-type playStartedMsg struct{}
+type playStartedMsg struct{ gen uint64 }
+type osdClearMsg struct{ id int }
+type quitDisarmMsg struct{ id, osdID int }
 type playErrMsg struct{ error }
-type trackEndedMsg struct{}
+
+// endReason describes why playback of a track stopped.
+type endReason int
+
+const (
+	endFinished  endReason = iota // played to the end
+	endTruncated                  // stream ended well short of the track's duration
+)
+
+// truncatedSlack is how far short of the duration a track may end and still count as finished.
+const truncatedSlack = 5.0 // seconds
+
+const (
+	volumeStep   = 5 // percent per keypress
+	osdDuration  = time.Second
+	playAllLimit = 500             // most palette matches tab queues at once
+	quitWindow   = 2 * time.Second // how long a first quit press waits for the second
+	artMissTTL   = 30 * time.Minute
+)
+
+// The smallest terminal the layout fits: the minimum nav, content and queue
+// widths plus two dividers, and header, status bar, now playing and a few
+// list rows.
+const (
+	minWidth  = 20 + 20 + 25 + 2
+	minHeight = 12
+)
+
+type trackEndedMsg struct {
+	gen     uint64
+	reason  endReason
+	elapsed float64 // seconds played when the track ended
+	err     error   // what ended it early, if anything; see player.Err
+}
 
 type coverArtMsg struct {
-	albumID string
+	albumID string // the artKey fetched
 	data    []byte
+	missing bool  // the server has no art for it; don't refetch for a while
+	err     error // the fetch failed otherwise, e.g. the network; retried later
 }
 
 // --- Commands ---
@@ -780,9 +1782,40 @@ func (m Model) runSync() tea.Msg {
 	if err != nil {
 		return syncErrMsg{err}
 	}
+	m.syncPodcasts()
+	if err := m.db.SetLastSyncTime(time.Now()); err != nil {
+		slog.Warn("recording sync time failed", "err", err)
+	}
 	return syncDoneMsg{result: result}
 }
 
+// probeCapabilities checks which optional endpoints the server implements.
+func (m Model) probeCapabilities() tea.Msg {
+	m.client.ProbeCapabilities()
+	return nil
+}
+
+// libraryStats gathers the figures for the info overlay.
+func (m Model) libraryStats() ui.LibraryStats {
+	var server string
+	if m.client != nil {
+		server = m.client.Server().String()
+	}
+	return ui.LibraryStats{
+		Artists:       m.db.ArtistCount(),
+		Albums:        m.db.AlbumCount(),
+		Tracks:        m.db.TrackCount(),
+		TotalDuration: m.db.TotalDuration(),
+		DBSize:        m.db.Size(),
+		Listened:      m.listened,
+		ListenedTotal: m.listenedTotal(),
+		LastSync:      m.db.LastSyncTime(),
+		ServerURL:     m.cfg.Subsonic.URL,
+		Server:        server,
+		Version:       Version,
+	}
+}
+
 func (m Model) playQueueTrack(track *ui.QueueTrack) tea.Cmd {
 	return func() tea.Msg {
 		if m.client == nil || m.player == nil || track == nil {
@@ -791,53 +1824,171 @@ func (m Model) playQueueTrack(track *ui.QueueTrack) tea.Cmd {
 
 		format := strings.ToLower(track.Format)
 		streamFormat := ""
-		if format == "m4a" || format == "aac" || format == "wma" {
+		switch {
+		case format == "m4a" || format == "aac" || format == "wma":
 			streamFormat = "mp3"
+		case track.ID == m.transcodeID:
+			// Its file stopped decoding part way through last time.
+			streamFormat = m.cfg.Playback.FallbackFormat
+			format = streamFormat
 		}
 
 		streamURL := m.client.StreamURL(track.ID, streamFormat)
 		info := player.NowPlaying{
-			TrackID:    track.ID,
-			Title:      track.Title,
-			Artist:     track.Artist,
-			Album:      track.Album,
-			AlbumID:    track.AlbumID,
-			Year:       track.Year,
-			DurationMs: track.DurationMs,
-			Format:     track.Format,
+			TrackID:      track.ID,
+			Title:        track.Title,
+			Artist:       track.Artist,
+			Album:        track.Album,
+			AlbumID:      track.AlbumID,
+			Year:         track.Year,
+			DurationMs:   track.DurationMs,
+			Format:       track.Format,
+			StreamFormat: streamFormat,
 		}
-
-		if err := m.player.Play(streamURL, format, info); err != nil {
+		at := m.startPosition(track)
+
+		gen, err := m.player.PlayAt(streamURL, format, info, at)
+		// The server may manage a file the decoders can't: try once more
+		// with it transcoding.
+		if fallback := m.cfg.Playback.FallbackFormat; errors.Is(err, player.ErrDecode) && streamFormat == "" && fallback != "" {
+			slog.Warn("decoding failed, retrying transcoded", "track", track.ID, "format", format, "fallback", fallback, "err", err)
+			info.StreamFormat = fallback
+			retryGen, retryErr := m.player.PlayAt(m.client.StreamURL(track.ID, fallback), fallback, info, at)
+			if retryErr == nil || errors.Is(retryErr, player.ErrSuperseded) {
+				gen, err = retryGen, retryErr
+			} else {
+				slog.Warn("transcoded retry failed", "track", track.ID, "err", retryErr)
+				err = fmt.Errorf("%w (and as %s: %w)", err, fallback, retryErr)
+			}
+		}
+		if err != nil {
+			if errors.Is(err, player.ErrSuperseded) {
+				return nil // a newer selection is already starting
+			}
 			return playErrMsg{err}
 		}
-		return playStartedMsg{}
+		return playStartedMsg{gen: gen}
 	}
 }
 
-func (m Model) fetchCoverArt(albumID string) tea.Cmd {
-	return func() tea.Msg {
-		if m.client == nil || albumID == "" {
-			return coverArtMsg{}
+// fetchCoverArt loads the art for a track's album by the cover art ID the
+// server gave the track, then the album's, falling back to the album ID for
+// servers that refuse or don't know those.
+func (m Model) fetchCoverArt(t *ui.QueueTrack) tea.Cmd {
+	albumID := artKey(t)
+	if m.client == nil || albumID == "" || m.albumArt.Backend() == ui.ArtOff {
+		return func() tea.Msg { return coverArtMsg{} }
+	}
+	var ids []string
+	for _, id := range []string{t.CoverArt, m.db.AlbumCoverArt(t.AlbumID), t.AlbumID} {
+		if id != "" && !slices.Contains(ids, id) {
+			ids = append(ids, id)
 		}
-		data, err := m.client.GetCoverArt(albumID, 256)
-		if err != nil {
-			slog.Debug("cover art fetch failed", "albumID", albumID, "err", err)
-			return coverArtMsg{albumID: albumID}
+	}
+	client := m.client
+	return func() tea.Msg {
+		var err error
+		for _, id := range ids {
+			var data []byte
+			data, err = client.GetCoverArt(id, 256)
+			if err == nil {
+				return coverArtMsg{albumID: albumID, data: data}
+			}
+			if !errors.Is(err, subsonic.ErrNoCoverArt) {
+				slog.Debug("cover art fetch failed", "albumID", albumID, "err", err)
+				return coverArtMsg{albumID: albumID, err: err}
+			}
 		}
-		return coverArtMsg{albumID: albumID, data: data}
+		slog.Debug("no cover art", "albumID", albumID, "err", err)
+		return coverArtMsg{albumID: albumID, missing: true}
+	}
+}
+
+// refreshArt fetches the playing track's art again, even if the server
+// recently had none.
+func (m *Model) refreshArt() tea.Cmd {
+	cur := m.queue.Current()
+	if cur == nil {
+		return m.flashOSD(m.text(msgNothingPlaying))
+	}
+	delete(m.artMisses, artKey(cur))
+	m.artAlbumID = ""
+	return tea.Batch(m.fetchCoverArt(cur), m.flashOSD(m.text(msgRefreshingArt)))
+}
+
+// artKey identifies the art a track shows: its album's, or for podcast
+// episodes, which have no album, its own cover art.
+func artKey(t *ui.QueueTrack) string {
+	if t.AlbumID != "" {
+		return t.AlbumID
+	}
+	return t.CoverArt
+}
+
+// artMissed reports whether the server recently had no art for albumID.
+// Misses expire after artMissTTL so art added on the server is eventually
+// picked up; failed fetches aren't misses and are retried.
+func (m Model) artMissed(albumID string) bool {
+	at, ok := m.artMisses[albumID]
+	if ok && time.Since(at) >= artMissTTL {
+		delete(m.artMisses, albumID)
+		return false
 	}
+	return ok
+}
+
+// tickCmd schedules the next tick in the current generation.
+func (m Model) tickCmd() tea.Cmd {
+	id := m.tickID
+	interval := time.Duration(m.cfg.UI.TickMs) * time.Millisecond
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return tickMsg{id: id}
+	})
+}
+
+func marqueeTick() tea.Cmd {
+	return tea.Tick(marqueeInterval, func(time.Time) tea.Msg { return marqueeMsg{} })
+}
+
+// frameCmd schedules the next seek bar frame, if frames are on.
+func (m Model) frameCmd() tea.Cmd {
+	if m.cfg.UI.FrameMs <= 0 {
+		return nil
+	}
+	id := m.tickID
+	interval := time.Duration(m.cfg.UI.FrameMs) * time.Millisecond
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return frameMsg{id: id}
+	})
+}
+
+// restartTick starts a new tick loop, retiring any loop already running,
+// along with its frames.
+func (m *Model) restartTick() tea.Cmd {
+	m.tickID++
+	return tea.Batch(m.tickCmd(), m.frameCmd())
 }
 
 func (m Model) waitForTrackEnd() tea.Msg {
 	if m.player == nil {
 		return nil
 	}
-	<-m.player.Done()
-	return trackEndedMsg{}
+	gen := <-m.player.Done()
+
+	msg := trackEndedMsg{gen: gen, reason: endFinished, elapsed: m.player.Elapsed(), err: m.player.Err()}
+	if cur := m.player.Current(); cur != nil && cur.DurationMs > 0 {
+		if msg.elapsed < float64(cur.DurationMs)/1000-truncatedSlack {
+			msg.reason = endTruncated
+		}
+	}
+	return msg
 }
 
 func formatDuration(ms int) string {
 	totalSec := ms / 1000
+	if totalSec >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", totalSec/3600, totalSec/60%60, totalSec%60)
+	}
 	min := totalSec / 60
 	sec := totalSec % 60
 	return fmt.Sprintf("%d:%02d", min, sec)
@@ -846,41 +1997,119 @@ func formatDuration(ms int) string {
 // --- Keybindings ---
 
 var keys = struct {
-	Quit     key.Binding
-	Pause    key.Binding
-	Palette  key.Binding
-	Tab      key.Binding
-	Up       key.Binding
-	Down     key.Binding
-	Expand   key.Binding
-	Collapse key.Binding
-	Toggle   key.Binding
-	Top      key.Binding
-	Bottom   key.Binding
-	HalfDown key.Binding
-	HalfUp   key.Binding
-	Remove   key.Binding
-	MoveUp   key.Binding
-	MoveDown key.Binding
-	Escape   key.Binding
-	Shuffle  key.Binding
+	Quit          key.Binding
+	Pause         key.Binding
+	Stop          key.Binding
+	Palette       key.Binding
+	Tab           key.Binding
+	Up            key.Binding
+	Down          key.Binding
+	Expand        key.Binding
+	Collapse      key.Binding
+	Toggle        key.Binding
+	Top           key.Binding
+	Bottom        key.Binding
+	ScreenTop     key.Binding
+	ScreenMiddle  key.Binding
+	HalfDown      key.Binding
+	HalfUp        key.Binding
+	Remove        key.Binding
+	MoveUp        key.Binding
+	MoveDown      key.Binding
+	Escape        key.Binding
+	Shuffle       key.Binding
+	ShuffleQueue  key.Binding
+	Info          key.Binding
+	SkipNext      key.Binding
+	SkipPrev      key.Binding
+	Follow        key.Binding
+	GoAlbum       key.Binding
+	GoArtist      key.Binding
+	NavNarrower   key.Binding
+	NavWider      key.Binding
+	QueueNarrower key.Binding
+	QueueWider    key.Binding
+	ResetPanels   key.Binding
+	Enqueue       key.Binding
+	PlayOnward    key.Binding
+	VolumeUp      key.Binding
+	VolumeDown    key.Binding
+	Mute          key.Binding
+	SeekBack      key.Binding
+	SeekFwd       key.Binding
+	SeekBackLarge key.Binding
+	SeekFwdLarge  key.Binding
+	Radio         key.Binding
+	AddToPlaylist key.Binding
+	ToggleFilter  key.Binding
+	Playlists     key.Binding
+	TopSongs      key.Binding
+	TimeMode      key.Binding
+	Compact       key.Binding
+	Bookmark      key.Binding
+	Bookmarks     key.Binding
+	AlbumsView    key.Binding
+	AlbumSort     key.Binding
+	Unplayed      key.Binding
+	Continue      key.Binding
+	Restart       key.Binding
+	RefreshArt    key.Binding
 }{
-	Quit:     key.NewBinding(key.WithKeys("q", "ctrl+c")),
-	Pause:    key.NewBinding(key.WithKeys(" ")),
-	Palette:  key.NewBinding(key.WithKeys("ctrl+p")),
-	Tab:      key.NewBinding(key.WithKeys("tab")),
-	Up:       key.NewBinding(key.WithKeys("k", "up")),
-	Down:     key.NewBinding(key.WithKeys("j", "down")),
-	Expand:   key.NewBinding(key.WithKeys("l", "right")),
-	Collapse: key.NewBinding(key.WithKeys("h", "left")),
-	Toggle:   key.NewBinding(key.WithKeys("enter")),
-	Top:      key.NewBinding(key.WithKeys("g")),
-	Bottom:   key.NewBinding(key.WithKeys("G")),
-	HalfDown: key.NewBinding(key.WithKeys("ctrl+d")),
-	HalfUp:   key.NewBinding(key.WithKeys("ctrl+u")),
-	Remove:   key.NewBinding(key.WithKeys("d")),
-	MoveUp:   key.NewBinding(key.WithKeys("K")),
-	MoveDown: key.NewBinding(key.WithKeys("J")),
-	Escape:   key.NewBinding(key.WithKeys("esc", "backspace")),
-	Shuffle:  key.NewBinding(key.WithKeys("s")),
+	Quit:          key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "quit")),
+	Pause:         key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "pause")),
+	Stop:          key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "stop")),
+	Palette:       key.NewBinding(key.WithKeys("ctrl+p"), key.WithHelp("ctrl+p", "search")),
+	Tab:           key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "switch")),
+	Up:            key.NewBinding(key.WithKeys("k", "up"), key.WithHelp("j/k", "move")),
+	Down:          key.NewBinding(key.WithKeys("j", "down")),
+	Expand:        key.NewBinding(key.WithKeys("l", "right")),
+	Collapse:      key.NewBinding(key.WithKeys("h", "left")),
+	Toggle:        key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "play")),
+	Top:           key.NewBinding(key.WithKeys("g")),
+	Bottom:        key.NewBinding(key.WithKeys("G")),
+	ScreenTop:     key.NewBinding(key.WithKeys("H")),
+	ScreenMiddle:  key.NewBinding(key.WithKeys("M")),
+	HalfDown:      key.NewBinding(key.WithKeys("ctrl+d")),
+	HalfUp:        key.NewBinding(key.WithKeys("ctrl+u")),
+	Remove:        key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "remove")),
+	MoveUp:        key.NewBinding(key.WithKeys("K"), key.WithHelp("K/J", "reorder")),
+	MoveDown:      key.NewBinding(key.WithKeys("J")),
+	Escape:        key.NewBinding(key.WithKeys("esc", "backspace")),
+	Shuffle:       key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "shuffle")),
+	ShuffleQueue:  key.NewBinding(key.WithKeys("S"), key.WithHelp("S", "shuffle")),
+	Info:          key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "info")),
+	SkipNext:      key.NewBinding(key.WithKeys(">"), key.WithHelp("</>", "skip")),
+	SkipPrev:      key.NewBinding(key.WithKeys("<")),
+	Follow:        key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "follow")),
+	GoAlbum:       key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "album")),
+	GoArtist:      key.NewBinding(key.WithKeys("O")),
+	NavNarrower:   key.NewBinding(key.WithKeys("alt+h"), key.WithHelp("alt+h/l", "nav width")),
+	NavWider:      key.NewBinding(key.WithKeys("alt+l")),
+	QueueNarrower: key.NewBinding(key.WithKeys("alt+L"), key.WithHelp("alt+H/L", "queue width")),
+	QueueWider:    key.NewBinding(key.WithKeys("alt+H")),
+	ResetPanels:   key.NewBinding(key.WithKeys("alt+0"), key.WithHelp("alt+0", "reset widths")),
+	Enqueue:       key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "add")),
+	PlayOnward:    key.NewBinding(key.WithKeys("D"), key.WithHelp("D", "play onward")),
+	VolumeUp:      key.NewBinding(key.WithKeys("+", "="), key.WithHelp("+/-", "vol")),
+	VolumeDown:    key.NewBinding(key.WithKeys("-")),
+	Mute:          key.NewBinding(key.WithKeys("m")),
+	SeekBack:      key.NewBinding(key.WithKeys("[")),
+	SeekFwd:       key.NewBinding(key.WithKeys("]"), key.WithHelp("[/]", "seek")),
+	SeekBackLarge: key.NewBinding(key.WithKeys("{", "shift+left")),
+	SeekFwdLarge:  key.NewBinding(key.WithKeys("}", "shift+right")),
+	Radio:         key.NewBinding(key.WithKeys("R")),
+	AddToPlaylist: key.NewBinding(key.WithKeys("P"), key.WithHelp("P", "playlist")),
+	ToggleFilter:  key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "all/artist")),
+	Playlists:     key.NewBinding(key.WithKeys("L")),
+	TopSongs:      key.NewBinding(key.WithKeys("T")),
+	TimeMode:      key.NewBinding(key.WithKeys("e")),
+	Compact:       key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "mini")),
+	Bookmark:      key.NewBinding(key.WithKeys("b")),
+	Bookmarks:     key.NewBinding(key.WithKeys("B")),
+	AlbumsView:    key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "albums")),
+	AlbumSort:     key.NewBinding(key.WithKeys("V")),
+	Unplayed:      key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "unplayed")),
+	Continue:      key.NewBinding(key.WithKeys("n")),
+	Restart:       key.NewBinding(key.WithKeys("0")),
+	RefreshArt:    key.NewBinding(key.WithKeys("ctrl+r")),
 }
@@ -0,0 +1,312 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/simonhull/kitsune/internal/config"
+	"github.com/simonhull/kitsune/internal/db"
+	"github.com/simonhull/kitsune/internal/player"
+	"github.com/simonhull/kitsune/internal/ui"
+)
+
+// newTestModel returns a model on an empty database of its own, with no
+// server or player.
+func newTestModel(t *testing.T) Model {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir()) // keys like alt+h save to the config
+	database, err := db.Open(nil)
+	if err != nil {
+		t.Fatalf("opening database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return New(config.Default(), database, nil, nil, nil)
+}
+
+// testAlbum is an album to seed a test library with, and its tracks.
+type testAlbum struct {
+	db.AlbumRow
+	Tracks []db.TrackRow
+}
+
+// seedLibrary adds albums, with their artists and tracks, to the model's
+// database. Tracks take their album's ID and name, and its artist unless
+// they name their own.
+func seedLibrary(t *testing.T, m Model, albums ...testAlbum) {
+	t.Helper()
+	exec := func(query string, args ...any) {
+		t.Helper()
+		if _, err := m.db.Conn.Exec(query, args...); err != nil {
+			t.Fatalf("seeding library: %v", err)
+		}
+	}
+	addArtist := func(id, name string) {
+		exec(`INSERT INTO artists (id, name) VALUES (?, ?) ON CONFLICT(id) DO NOTHING`, id, name)
+	}
+	for _, a := range albums {
+		addArtist(a.ArtistID, a.ArtistName)
+		exec(`UPDATE artists SET album_count = album_count + 1 WHERE id = ?`, a.ArtistID)
+		exec(`INSERT INTO albums (id, name, artist_id, artist_name, year, song_count, cover_art) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			a.ID, a.Name, a.ArtistID, a.ArtistName, a.Year, len(a.Tracks), a.CoverArt)
+		for _, tr := range a.Tracks {
+			if tr.ArtistID == "" {
+				tr.ArtistID, tr.Artist = a.ArtistID, a.ArtistName
+			}
+			addArtist(tr.ArtistID, tr.Artist)
+			exec(`INSERT INTO tracks (id, title, artist, album, album_id, artist_id, track_num, disc_num, duration_ms, format)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				tr.ID, tr.Title, tr.Artist, a.Name, a.ID, tr.ArtistID, tr.TrackNum, tr.DiscNum, tr.DurationMs, tr.Format)
+		}
+	}
+}
+
+func TestTrackEndedIgnoresStaleGeneration(t *testing.T) {
+	m := newTestModel(t)
+	m.queue.Replace([]ui.QueueTrack{{ID: "a"}, {ID: "b"}, {ID: "c"}}, 0)
+	m.playGen = 3
+
+	// Plays overlapped: the tracks of generations 1 and 2 were replaced by
+	// 3, but their end signals still arrive.
+	for _, gen := range []uint64{1, 2} {
+		model, _ := m.Update(trackEndedMsg{gen: gen, reason: endFinished})
+		m = model.(Model)
+		if cur := m.queue.Current(); cur == nil || cur.ID != "a" {
+			t.Fatalf("generation %d's end moved the queue to %v", gen, cur)
+		}
+	}
+
+	model, cmd := m.Update(trackEndedMsg{gen: 3, reason: endFinished})
+	m = model.(Model)
+	if cur := m.queue.Current(); cur == nil || cur.ID != "b" {
+		t.Fatalf("current generation's end left the queue at %v, want b", cur)
+	}
+	if cmd == nil {
+		t.Error("no command to play the next track")
+	}
+}
+
+func TestTruncatedTrackRetriesThenAdvances(t *testing.T) {
+	m := newTestModel(t)
+	m.queue.Replace([]ui.QueueTrack{{ID: "a"}, {ID: "b"}}, 0)
+	m.playGen = 1
+	end := trackEndedMsg{gen: 1, reason: endTruncated, elapsed: 42}
+
+	// The first cut-off retries the track from where it stopped.
+	model, cmd := m.Update(end)
+	m = model.(Model)
+	if cur := m.queue.Current(); cur == nil || cur.ID != "a" {
+		t.Fatalf("first cut-off moved the queue to %v", cur)
+	}
+	if cmd == nil {
+		t.Fatal("no command to retry the track")
+	}
+	if got := m.startPosition(m.queue.Current()); got != 42*time.Second {
+		t.Errorf("retry starts at %v, want 42s", got)
+	}
+
+	// Cut off again, it gives up on the track but not the queue.
+	model, cmd = m.Update(end)
+	m = model.(Model)
+	if cur := m.queue.Current(); cur == nil || cur.ID != "b" {
+		t.Fatalf("second cut-off left the queue at %v, want b", cur)
+	}
+	if cmd == nil {
+		t.Error("no command to play the next track")
+	}
+	if m.osd != m.text(msgEndedEarly, "0:42") {
+		t.Errorf("OSD = %q, want the cut-off reported", m.osd)
+	}
+
+	// With nothing after it, the error stays up.
+	model, _ = m.Update(end)
+	m = model.(Model)
+	model, cmd = m.Update(end)
+	m = model.(Model)
+	if cmd != nil {
+		t.Error("a command after the last track was cut off twice")
+	}
+	if m.playErr != m.text(msgEndedEarly, "0:42") {
+		t.Errorf("playErr = %q, want the cut-off reported", m.playErr)
+	}
+}
+
+func TestDecodeErrorRetriesTranscoded(t *testing.T) {
+	m := newTestModel(t)
+	m.queue.Replace([]ui.QueueTrack{{ID: "a", Format: "flac"}, {ID: "b"}}, 0)
+	m.playGen = 1
+	end := trackEndedMsg{
+		gen:     1,
+		reason:  endTruncated,
+		elapsed: 90,
+		err:     fmt.Errorf("%w: bad frame", player.ErrDecode),
+	}
+
+	// The first decode error retries the track transcoded, from where it
+	// stopped.
+	model, cmd := m.Update(end)
+	m = model.(Model)
+	if cur := m.queue.Current(); cur == nil || cur.ID != "a" {
+		t.Fatalf("decode error moved the queue to %v", cur)
+	}
+	if cmd == nil {
+		t.Fatal("no command to retry the track")
+	}
+	if m.transcodeID != "a" {
+		t.Errorf("transcodeID = %q, want a", m.transcodeID)
+	}
+	if got := m.startPosition(m.queue.Current()); got != 90*time.Second {
+		t.Errorf("retry starts at %v, want 1m30s", got)
+	}
+
+	// Failing again as transcoded, it's treated as any cut-off stream.
+	model, _ = m.Update(end)
+	m = model.(Model)
+	if m.retriedID != "a" {
+		t.Errorf("second failure didn't take the plain retry")
+	}
+
+	// Without a fallback format there's nothing to transcode to.
+	m = newTestModel(t)
+	m.cfg.Playback.FallbackFormat = ""
+	m.queue.Replace([]ui.QueueTrack{{ID: "a", Format: "flac"}}, 0)
+	m.playGen = 1
+	model, _ = m.Update(end)
+	m = model.(Model)
+	if m.transcodeID != "" || m.retriedID != "a" {
+		t.Errorf("with no fallback: transcodeID %q, retriedID %q; want a plain retry", m.transcodeID, m.retriedID)
+	}
+}
+
+func TestEnqueueTrack(t *testing.T) {
+	m := newTestModel(t)
+	seedLibrary(t, m, testAlbum{
+		AlbumRow: db.AlbumRow{ID: "al1", Name: "Secret Name", ArtistID: "ar1", ArtistName: "Low"},
+		Tracks: []db.TrackRow{
+			{ID: "t1", Title: "I Remember", TrackNum: 1},
+			{ID: "t2", Title: "Starfire", TrackNum: 2},
+		},
+	})
+	m.content = ui.NewContentBrowser(m.db, &m.styles, false)
+	m.content.ScrollToTrack("t2")
+	m.setFocus(focusContent)
+	m.queue.Replace([]ui.QueueTrack{{ID: "x"}}, 0)
+
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	m = model.(Model)
+	if m.queue.Len() != 2 {
+		t.Fatalf("queue has %d tracks after adding one to one, want 2", m.queue.Len())
+	}
+	if cur := m.queue.Current(); cur == nil || cur.ID != "x" {
+		t.Errorf("adding a track changed the current track to %v", cur)
+	}
+	m.queue.Skip(1)
+	if cur := m.queue.Current(); cur == nil || cur.ID != "t2" {
+		t.Errorf("added %v, want just t2", cur)
+	}
+}
+
+func TestClickTotalTimeTogglesRemaining(t *testing.T) {
+	for _, dense := range []bool{false, true} {
+		m := newTestModel(t)
+		m.nowPlaying.SetDense(dense)
+		model, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+		m = model.(Model)
+		m.queue.Replace([]ui.QueueTrack{{ID: "t1", Title: "Song", DurationMs: 180000}}, 0)
+
+		lines := strings.Split(m.View(), "\n")
+		y := 2 + m.contentHeight() + m.nowPlaying.SeekRow()
+		if y >= len(lines) || !strings.Contains(lines[y], "3:00") {
+			t.Fatalf("dense %v: row %d isn't the seek bar", dense, y)
+		}
+		m, _ = m.handleMouseClick(m.width-m.nowPlaying.RightInset()-3, y)
+		if !m.remaining {
+			t.Errorf("dense %v: clicking the total time didn't switch to time remaining", dense)
+		}
+	}
+}
+
+func TestConfirmQuit(t *testing.T) {
+	q := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}
+	isQuit := func(cmd tea.Cmd) bool {
+		if cmd == nil {
+			return false
+		}
+		_, ok := cmd().(tea.QuitMsg)
+		return ok
+	}
+
+	m := newTestModel(t)
+	m.cfg.UI.ConfirmQuit = true
+
+	// Nothing is loaded: the first q arms, the second quits.
+	model, _ := m.Update(q)
+	m = model.(Model)
+	if !m.quitArmed || m.osd != m.text(msgQuitAgain) {
+		t.Fatalf("first q: armed %v, osd %q", m.quitArmed, m.osd)
+	}
+	if _, cmd := m.Update(q); !isQuit(cmd) {
+		t.Error("second q didn't quit")
+	}
+
+	// Any other key disarms.
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	m = model.(Model)
+	if m.quitArmed {
+		t.Error("another key left quitting armed")
+	}
+
+	// So does the window running out.
+	model, _ = m.Update(q)
+	m = model.(Model)
+	model, _ = m.Update(quitDisarmMsg{m.quitID, m.osdID})
+	m = model.(Model)
+	if m.quitArmed || m.osd != "" {
+		t.Errorf("after the window: armed %v, osd %q", m.quitArmed, m.osd)
+	}
+
+	// With a track loaded the prompt asks y/n, and y quits.
+	m.queue.Replace([]ui.QueueTrack{{ID: "t1", Title: "Song"}}, 0)
+	model, _ = m.Update(q)
+	m = model.(Model)
+	if m.osd != m.text(msgQuitPrompt) {
+		t.Errorf("osd %q while playing, want the y/n prompt", m.osd)
+	}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}); !isQuit(cmd) {
+		t.Error("y didn't quit")
+	}
+}
+
+func TestOnSelectArtistAfterTopSongs(t *testing.T) {
+	m := newTestModel(t)
+	m.cfg.Playback.OnSelect = "artist"
+	seedLibrary(t, m, testAlbum{
+		AlbumRow: db.AlbumRow{ID: "al1", Name: "Secret Name", ArtistID: "ar1", ArtistName: "Low"},
+		Tracks:   []db.TrackRow{{ID: "t1", TrackNum: 1}, {ID: "t2", TrackNum: 2}},
+	})
+	tracks, err := m.db.TracksForAlbum("al1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.selectTracks(tracks, 0, "ar1")
+	m.startTopSongs("Someone Else", []ui.QueueTrack{{ID: "x"}}, false)
+
+	// The top songs replaced Low's album, so picking Low again is a new
+	// artist and adds to them rather than replacing them.
+	m.selectTracks(tracks, 1, "ar1")
+	if got := m.queue.IDs(); len(got) != 3 || got[0] != "x" {
+		t.Errorf("queue is %v, want the top songs then Low's album", got)
+	}
+	if cur := m.queue.Current(); cur == nil || cur.ID != "t2" {
+		t.Errorf("playing %v, want t2", cur)
+	}
+
+	// Picking Low once more replaces, as the queue was last filled from Low.
+	m.selectTracks(tracks, 0, "ar1")
+	if got := m.queue.IDs(); len(got) != 2 {
+		t.Errorf("queue is %v, want just Low's album", got)
+	}
+}
@@ -0,0 +1,140 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/simonhull/kitsune/internal/ui"
+)
+
+// playingPosition returns the current track and how far into it playback
+// is, counting a stopped track's saved resume point.
+func (m *Model) playingPosition() (*ui.QueueTrack, time.Duration) {
+	cur := m.queue.Current()
+	if cur == nil {
+		return nil, 0
+	}
+	if m.stopped {
+		if m.resumeID == cur.ID {
+			return cur, m.resumeAt
+		}
+		return cur, 0
+	}
+	if m.player == nil {
+		return cur, 0
+	}
+	return cur, time.Duration(m.player.Elapsed() * float64(time.Second))
+}
+
+// addBookmark marks the current position in the playing track. An empty
+// name uses the timestamp.
+func (m *Model) addBookmark(name string) tea.Cmd {
+	cur, pos := m.playingPosition()
+	if cur == nil {
+		return m.flashOSD(m.text(msgNothingPlaying))
+	}
+	if name == "" {
+		name = formatDuration(int(pos.Milliseconds()))
+	}
+	if err := m.db.AddBookmark(cur.ID, name, pos); err != nil {
+		m.playErr = fmt.Sprintf("saving bookmark: %v", err)
+		return nil
+	}
+	m.loadBookmarks()
+	return m.flashOSD(m.text(msgBookmarked, name))
+}
+
+// loadBookmarks reads the current track's bookmarks for the seek bar and
+// the bookmark list.
+func (m *Model) loadBookmarks() {
+	m.marks = nil
+	cur := m.queue.Current()
+	if cur == nil {
+		return
+	}
+	marks, err := m.db.Bookmarks(cur.ID)
+	if err != nil {
+		m.playErr = fmt.Sprintf("bookmarks: %v", err)
+		return
+	}
+	m.marks = marks
+}
+
+// markSeconds returns the bookmark positions for the seek bar, if they
+// belong to trackID.
+func (m Model) markSeconds(trackID string) []float64 {
+	var secs []float64
+	for _, b := range m.marks {
+		if b.TrackID == trackID {
+			secs = append(secs, b.Position.Seconds())
+		}
+	}
+	return secs
+}
+
+// openBookmarks lists the current track's bookmarks to jump to or delete.
+func (m *Model) openBookmarks() tea.Cmd {
+	cur := m.queue.Current()
+	if cur == nil {
+		return m.flashOSD(m.text(msgNothingPlaying))
+	}
+	m.loadBookmarks()
+	if len(m.marks) == 0 {
+		return m.flashOSD(m.text(msgNoBookmarks))
+	}
+
+	items := make([]ui.PickerItem, len(m.marks))
+	for i, b := range m.marks {
+		items[i] = ui.PickerItem{
+			ID:     strconv.FormatInt(b.ID, 10),
+			Label:  b.Name,
+			Detail: formatDuration(int(b.Position.Milliseconds())),
+		}
+	}
+	m.pickerMode = pickBookmark
+	m.picker.SetSize(m.width, m.contentHeight())
+	m.picker.Open("Bookmarks: "+cur.Title, items)
+	return nil
+}
+
+// jumpToBookmark seeks the current track to the highlighted bookmark,
+// starting it first if it's stopped.
+func (m *Model) jumpToBookmark() tea.Cmd {
+	idx := m.picker.Cursor()
+	if idx < 0 || idx >= len(m.marks) {
+		return nil
+	}
+	b := m.marks[idx]
+	m.picker.Close()
+
+	cur, pos := m.playingPosition()
+	if cur == nil || cur.ID != b.TrackID {
+		return nil
+	}
+	if m.stopped || m.player == nil || !m.player.IsPlaying() {
+		m.resumeID, m.resumeAt = cur.ID, b.Position
+		return m.playQueueTrack(cur)
+	}
+	if _, err := m.player.Seek(b.Position - pos); err != nil {
+		return m.flashOSD(m.seekError(err))
+	}
+	return m.flashOSD(b.Name)
+}
+
+// deleteBookmark removes the highlighted bookmark.
+func (m *Model) deleteBookmark() tea.Cmd {
+	idx := m.picker.Cursor()
+	if idx < 0 || idx >= len(m.marks) {
+		return nil
+	}
+	b := m.marks[idx]
+	if err := m.db.DeleteBookmark(b.ID); err != nil {
+		m.playErr = fmt.Sprintf("deleting bookmark: %v", err)
+		return nil
+	}
+	m.marks = append(m.marks[:idx:idx], m.marks[idx+1:]...)
+	m.picker.Remove(idx)
+	return m.flashOSD(m.text(msgDeleted, b.Name))
+}
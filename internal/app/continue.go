@@ -0,0 +1,61 @@
+package app
+
+import (
+	"log/slog"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/simonhull/kitsune/internal/db"
+	"github.com/simonhull/kitsune/internal/ui"
+)
+
+// toggleContinueArtist turns artist continuation on or off: when a queue
+// of one artist's music runs out, their next album is queued and played.
+func (m *Model) toggleContinueArtist() tea.Cmd {
+	m.continueArtist = !m.continueArtist
+	if m.continueArtist {
+		return m.flashOSD(m.text(msgContinueOn))
+	}
+	return m.flashOSD(m.text(msgContinueOff))
+}
+
+// nextArtistAlbum queues the album after last's in its artist's
+// discography, when continuation is on and everything queued was that
+// artist's, and returns the track to play. It returns nil otherwise, or at
+// the end of the discography.
+func (m *Model) nextArtistAlbum(last ui.QueueTrack) *ui.QueueTrack {
+	if !m.continueArtist || m.radio {
+		return nil
+	}
+	// Compilations are filed under the album's artist, not the track's.
+	artistID := m.db.AlbumArtistID(last.AlbumID)
+	if artistID == "" {
+		return nil
+	}
+	for _, albumID := range m.queue.AlbumIDs() {
+		if m.db.AlbumArtistID(albumID) != artistID {
+			return nil
+		}
+	}
+
+	albums, err := m.db.AlbumsForArtist(artistID)
+	if err != nil {
+		slog.Warn("continue artist", "err", err)
+		return nil
+	}
+	i := slices.IndexFunc(albums, func(a db.AlbumRow) bool { return a.ID == last.AlbumID })
+	if i < 0 || i+1 >= len(albums) {
+		return nil
+	}
+	tracks, err := m.db.TracksForAlbum(albums[i+1].ID)
+	if err != nil || len(tracks) == 0 {
+		return nil
+	}
+
+	// Back on the finished track first, so that a full queue drops what has
+	// played to make room; the new album's start is only known after that.
+	m.queue.PlayAt(m.queue.Len() - 1)
+	m.queue.Append(toQueueTracks(tracks)...)
+	m.resizePanels()
+	return m.queue.PlayAt(m.queue.Len() - len(tracks))
+}
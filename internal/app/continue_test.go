@@ -0,0 +1,53 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/simonhull/kitsune/internal/db"
+)
+
+func TestContinueArtistIntoFullQueue(t *testing.T) {
+	m := newTestModel(t)
+	seedLibrary(t, m,
+		testAlbum{
+			AlbumRow: db.AlbumRow{ID: "al1", Name: "Kid A", ArtistID: "ar1", ArtistName: "Radiohead", Year: 2000},
+			Tracks: []db.TrackRow{
+				{ID: "a1", Title: "Everything in Its Right Place", TrackNum: 1},
+				{ID: "a2", Title: "Kid A", TrackNum: 2},
+				{ID: "a3", Title: "The National Anthem", TrackNum: 3},
+			},
+		},
+		testAlbum{
+			AlbumRow: db.AlbumRow{ID: "al2", Name: "Amnesiac", ArtistID: "ar1", ArtistName: "Radiohead", Year: 2001},
+			Tracks: []db.TrackRow{
+				{ID: "b1", Title: "Packt Like Sardines in a Crushd Tin Box", TrackNum: 1},
+				{ID: "b2", Title: "Pyramid Song", TrackNum: 2},
+			},
+		},
+	)
+	tracks, err := m.db.TracksForAlbum("al1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.continueArtist = true
+	m.queue.SetMaxLen(len(tracks))
+	m.queue.Replace(toQueueTracks(tracks), len(tracks)-1)
+	m.playGen = 1
+
+	// The queue is at its limit, so Amnesiac only fits once played tracks
+	// are dropped from the front.
+	model, cmd := m.Update(trackEndedMsg{gen: 1, reason: endFinished})
+	m = model.(Model)
+	if cur := m.queue.Current(); cur == nil || cur.ID != "b1" {
+		t.Fatalf("continuation is playing %v, want b1", cur)
+	}
+	if cmd == nil {
+		t.Error("no command to play the next album")
+	}
+	if got := m.queue.Len(); got != len(tracks) {
+		t.Errorf("queue has %d tracks, want it trimmed to %d", got, len(tracks))
+	}
+	if got := m.queue.IDs(); got[len(got)-1] != "b2" {
+		t.Errorf("queue is %v, want it to end with the new album", got)
+	}
+}
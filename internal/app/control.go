@@ -0,0 +1,124 @@
+package app
+
+import (
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/simonhull/kitsune/internal/control"
+)
+
+// togglePause pauses or resumes the current track.
+func (m *Model) togglePause() tea.Cmd {
+	m.player.TogglePause()
+	m.paused = !m.paused
+	if !m.paused {
+		return m.restartTick()
+	}
+	return nil
+}
+
+// stop halts playback, keeping the queue and remembering the position so
+// playing the same track again resumes there.
+func (m *Model) stop() tea.Cmd {
+	m.saveEpisodePosition(true)
+	m.resumeID = m.queue.Current().ID
+	m.resumeAt = time.Duration(m.player.Elapsed() * float64(time.Second))
+	m.player.Stop()
+	m.stopped = true
+	m.paused = false
+	return m.flashOSD(m.text(msgStopped))
+}
+
+// restart plays the current track again from the start: by seeking back
+// when the stream can, otherwise by starting it over. A stopped track starts
+// from the top rather than its saved position.
+func (m *Model) restart() tea.Cmd {
+	cur := m.queue.Current()
+	if !m.stopped && m.seekable {
+		elapsed := time.Duration(m.player.Elapsed() * float64(time.Second))
+		if _, err := m.player.Seek(-elapsed); err == nil {
+			// Restart the tick so the bar and position redraw from zero right away.
+			return tea.Batch(m.flashOSD(m.text(msgRestart)), m.restartTick())
+		}
+	}
+	m.resumeID, m.resumeAt = "", 0
+	if cur.Podcast {
+		if err := m.db.SetEpisodePosition(cur.ID, 0); err != nil {
+			slog.Warn("clearing episode position failed", "episode", cur.ID, "err", err)
+		}
+	}
+	return m.playQueueTrack(cur)
+}
+
+// handleCommand runs a command from the control server.
+func (m *Model) handleCommand(action string) tea.Cmd {
+	cur := m.queue.Current()
+	if m.player == nil || cur == nil {
+		return nil
+	}
+
+	switch action {
+	case "play":
+		if m.stopped {
+			return m.playQueueTrack(cur)
+		}
+		if m.paused {
+			return m.togglePause()
+		}
+	case "pause":
+		if !m.stopped && !m.paused {
+			return m.togglePause()
+		}
+	case "toggle":
+		if m.stopped {
+			return m.playQueueTrack(cur)
+		}
+		return m.togglePause()
+	case "stop":
+		if !m.stopped {
+			return m.stop()
+		}
+	case "next", "prev":
+		n := 1
+		if action == "prev" {
+			n = -1
+		}
+		if track := m.queue.Skip(n); track != nil {
+			return m.playQueueTrack(track)
+		}
+	case "restart":
+		return m.restart()
+	}
+	return nil
+}
+
+// publishStatus hands the current playback state to the control server.
+func (m Model) publishStatus() {
+	if m.control == nil {
+		return
+	}
+
+	status := control.Status{
+		State:       "stopped",
+		QueueLength: m.queue.Len(),
+		QueueIndex:  m.queue.CurrentIndex(),
+	}
+	if cur := m.queue.Current(); cur != nil {
+		status.Track = &control.Track{
+			ID:         cur.ID,
+			Title:      cur.Title,
+			Artist:     cur.Artist,
+			Album:      cur.Album,
+			DurationMs: cur.DurationMs,
+		}
+		switch {
+		case m.stopped:
+		case m.paused:
+			status.State = "paused"
+		default:
+			status.State = "playing"
+		}
+	}
+	m.control.SetStatus(status)
+}
@@ -0,0 +1,75 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/simonhull/kitsune/internal/db"
+	"github.com/simonhull/kitsune/internal/ui"
+)
+
+// duplicateTolerance is how far apart two copies' durations may be.
+const duplicateTolerance = 2 * time.Second
+
+// openDuplicates lists tracks that appear to be in the library more than
+// once. The server owns the files, so this only reviews: choosing a copy
+// shows it in the browser.
+func (m *Model) openDuplicates() tea.Cmd {
+	groups, err := m.db.FindDuplicates(duplicateTolerance)
+	if err != nil {
+		m.playErr = fmt.Sprintf("duplicates: %v", err)
+		return nil
+	}
+	if len(groups) == 0 {
+		return m.flashOSD(m.text(msgNoDuplicates))
+	}
+
+	m.dupes = nil
+	var items []ui.PickerItem
+	for _, group := range groups {
+		for i, t := range group {
+			details := []string{t.Album, formatDuration(t.DurationMs)}
+			if t.Format != "" {
+				details = append(details, t.Format)
+			}
+			details = append(details, fmt.Sprintf("%d/%d", i+1, len(group)))
+			items = append(items, ui.PickerItem{
+				ID:     t.ID,
+				Label:  t.Title + " — " + t.Artist,
+				Detail: strings.Join(details, " · "),
+			})
+			m.dupes = append(m.dupes, t)
+		}
+	}
+	m.pickerMode = pickDuplicate
+	m.picker.SetSize(m.width, m.contentHeight())
+	m.picker.Open(fmt.Sprintf("%d possible %s", len(groups), plural(len(groups), "duplicate", "duplicates")), items)
+	return nil
+}
+
+// revealTrack filters the browser to the artist the track's album is filed
+// under and focuses it on the track.
+func (m *Model) revealTrack(t db.TrackRow) {
+	artistID := m.browserArtist(t.AlbumID, t.ArtistID)
+	if m.content == nil || artistID == "" {
+		return
+	}
+	if m.nav != nil {
+		m.nav.SelectByID(artistID)
+	}
+	m.content.FilterByArtist(artistID)
+	m.content.ScrollToTrack(t.ID)
+	m.setFocus(focusContent)
+}
+
+// browserArtist returns the artist whose section of the browser holds an
+// album: the album's own artist, which for a compilation isn't the artist of
+// the track being looked for. artistID is the fallback for unknown albums.
+func (m *Model) browserArtist(albumID, artistID string) string {
+	if id := m.db.AlbumArtistID(albumID); id != "" {
+		return id
+	}
+	return artistID
+}
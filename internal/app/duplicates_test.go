@@ -0,0 +1,75 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/simonhull/kitsune/internal/db"
+	"github.com/simonhull/kitsune/internal/ui"
+)
+
+func TestRevealDuplicateAcrossAlbums(t *testing.T) {
+	m := newTestModel(t)
+
+	// The same album twice: once under its artist and once filed under
+	// Various Artists, as a compilation. Creep is on both, by Radiohead.
+	seedLibrary(t, m,
+		testAlbum{
+			AlbumRow: db.AlbumRow{ID: "al1", Name: "Pablo Honey", ArtistID: "ar1", ArtistName: "Radiohead"},
+			Tracks: []db.TrackRow{
+				{ID: "t3", Title: "You", TrackNum: 1, DurationMs: 208000},
+				{ID: "t1", Title: "Creep", TrackNum: 2, DurationMs: 238000},
+			},
+		},
+		testAlbum{
+			AlbumRow: db.AlbumRow{ID: "al2", Name: "Pablo Honey", ArtistID: "ar2", ArtistName: "Various Artists"},
+			Tracks: []db.TrackRow{
+				{ID: "t2", Title: "Creep", Artist: "Radiohead", ArtistID: "ar1", TrackNum: 2, DurationMs: 239000},
+			},
+		},
+	)
+	m.nav = ui.NewArtistNav(m.db, &m.styles)
+	m.content = ui.NewContentBrowser(m.db, &m.styles, false)
+
+	m.openDuplicates()
+	if m.pickerMode != pickDuplicate || !m.picker.IsOpen() {
+		t.Fatal("openDuplicates didn't open the picker")
+	}
+	if len(m.dupes) != 2 || m.dupes[0].ID != "t1" || m.dupes[1].ID != "t2" {
+		t.Fatalf("duplicates = %+v, want t1 and t2", m.dupes)
+	}
+
+	for _, tc := range []struct {
+		pick   int
+		artist string
+		track  string
+		album  string
+	}{
+		{0, "ar1", "t1", "al1"},
+		// The compilation's copy is under the album's artist, not the
+		// track's.
+		{1, "ar2", "t2", "al2"},
+	} {
+		m.openDuplicates()
+		for range tc.pick {
+			m.picker.CursorDown()
+		}
+		m.pickerChoose()
+
+		if m.picker.IsOpen() {
+			t.Error("picker still open after choosing a duplicate")
+		}
+		if got := m.content.FilterArtistID(); got != tc.artist {
+			t.Errorf("copy %d: browser filtered to %q, want %q", tc.pick, got, tc.artist)
+		}
+		if got := m.nav.SelectedID(); got != tc.artist {
+			t.Errorf("copy %d: artist list selects %q, want %q", tc.pick, got, tc.artist)
+		}
+		row := m.content.CursorRow()
+		if row == nil || row.TrackID != tc.track || row.AlbumID != tc.album {
+			t.Errorf("copy %d: browser cursor on %+v, want %s on %s", tc.pick, row, tc.track, tc.album)
+		}
+		if m.focus != focusContent {
+			t.Errorf("copy %d: focus %v, want the browser", tc.pick, m.focus)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package app
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// hint is one "key: action" entry in the status bar.
+type hint struct {
+	binding key.Binding
+	desc    string // overrides the binding's help text, e.g. enter is "open" in the nav
+}
+
+// focusHints are the hints for each panel, shown ahead of globalHints.
+var focusHints = map[focus][]hint{
+	focusArtistNav: {{binding: keys.Up}, {binding: keys.Toggle, desc: "open"}},
+	focusContent: {
+		{binding: keys.Up}, {binding: keys.Toggle}, {binding: keys.Enqueue}, {binding: keys.PlayOnward},
+		{binding: keys.Shuffle}, {binding: keys.ToggleFilter}, {binding: keys.AlbumsView}, {binding: keys.Unplayed},
+		{binding: keys.AddToPlaylist},
+	},
+	focusQueue: {
+		{binding: keys.Up}, {binding: keys.Toggle}, {binding: keys.Remove}, {binding: keys.MoveUp},
+		{binding: keys.GoAlbum}, {binding: keys.Follow}, {binding: keys.ShuffleQueue},
+	},
+}
+
+// globalHints apply whatever has focus.
+var globalHints = []hint{
+	{binding: keys.Pause}, {binding: keys.Stop}, {binding: keys.SkipNext}, {binding: keys.SeekFwd},
+	{binding: keys.VolumeUp}, {binding: keys.Tab}, {binding: keys.Palette}, {binding: keys.Info},
+	{binding: keys.Quit}, {binding: keys.NavNarrower}, {binding: keys.QueueNarrower}, {binding: keys.ResetPanels},
+}
+
+// compactHints replace the others in the mini layout.
+var compactHints = []hint{
+	{binding: keys.Pause}, {binding: keys.SkipNext}, {binding: keys.SeekFwd}, {binding: keys.VolumeUp},
+	{binding: keys.Compact, desc: "full"}, {binding: keys.Quit},
+}
+
+// compactKeys are the bindings the mini layout responds to.
+var compactKeys = []key.Binding{
+	keys.Quit, keys.Pause, keys.Stop, keys.SkipNext, keys.SkipPrev, keys.SeekBack, keys.SeekFwd,
+	keys.SeekBackLarge, keys.SeekFwdLarge, keys.VolumeUp, keys.VolumeDown, keys.Mute, keys.TimeMode,
+	keys.Compact, keys.Restart,
+}
+
+// statusHints composes the status bar hints for the focused panel from the
+// enabled bindings' help, leaving off trailing hints past width columns so
+// the bar stays one row.
+func (m Model) statusHints(width int) string {
+	all := slices.Concat(focusHints[m.focus], globalHints)
+	if m.compact {
+		all = compactHints
+	}
+	var b strings.Builder
+	for _, h := range all {
+		if !h.binding.Enabled() {
+			continue
+		}
+		if !m.seekable && !m.stopped && m.queue.Current() != nil && h.binding.Help() == keys.SeekFwd.Help() {
+			continue // the playing stream can't seek
+		}
+		help := h.binding.Help()
+		desc := h.desc
+		if desc == "" {
+			desc = help.Desc
+		}
+		part := help.Key + ": " + desc
+		if b.Len() > 0 {
+			part = "  " + part
+		}
+		if lipgloss.Width(b.String()+part) > width {
+			break
+		}
+		b.WriteString(part)
+	}
+	return b.String()
+}
@@ -0,0 +1,57 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestStatusBarFitsWidth(t *testing.T) {
+	m := newTestModel(t)
+	m.width, m.height = 80, 24
+
+	for _, tc := range []struct {
+		name    string
+		focus   focus
+		playErr string
+	}{
+		{"artists", focusArtistNav, ""},
+		{"content", focusContent, ""},
+		{"queue", focusQueue, ""},
+		{"long error", focusContent, "stream returned 503: " + strings.Repeat("unavailable ", 10)},
+	} {
+		m.focus, m.playErr = tc.focus, tc.playErr
+		bar := m.statusView()
+		if h := lipgloss.Height(bar) - m.styles.Status.GetVerticalFrameSize(); h != 1 {
+			t.Errorf("%s: status bar text is %d rows at 80 columns, want 1:\n%s", tc.name, h, bar)
+		}
+		if w := lipgloss.Width(bar); w > m.width {
+			t.Errorf("%s: status bar is %d columns wide, want at most %d", tc.name, w, m.width)
+		}
+	}
+
+	// Hints that don't fit are dropped from the end, not the start.
+	m.focus, m.playErr = focusContent, ""
+	hints := m.statusHints(40)
+	if lipgloss.Width(hints) > 40 {
+		t.Errorf("statusHints(40) = %q, %d columns", hints, lipgloss.Width(hints))
+	}
+	if !strings.HasPrefix(m.statusHints(1000), hints) {
+		t.Errorf("statusHints(40) = %q isn't the start of the full hints", hints)
+	}
+	if m.statusHints(0) != "" {
+		t.Errorf("statusHints(0) = %q, want nothing", m.statusHints(0))
+	}
+}
+
+func TestViewFitsTerminal(t *testing.T) {
+	m := newTestModel(t)
+	model, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m = model.(Model)
+	m.focus = focusContent
+	if h := lipgloss.Height(m.View()); h > 24 {
+		t.Errorf("view is %d rows in a 24 row terminal", h)
+	}
+}
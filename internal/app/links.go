@@ -0,0 +1,33 @@
+package app
+
+import (
+	"net/url"
+
+	"github.com/simonhull/kitsune/internal/subsonic"
+	"github.com/simonhull/kitsune/internal/ui"
+)
+
+// newLinker builds the hyperlink targets for [ui] hyperlinks: "server" links
+// to the server's web UI (Navidrome's layout), "musicbrainz" to a MusicBrainz
+// search by name. Anything else, or "server" without a server whose web UI
+// we know, turns links off.
+func newLinker(mode string, client *subsonic.Client) ui.Linker {
+	switch mode {
+	case "server":
+		if client == nil || client.Server().Type != "navidrome" {
+			return nil
+		}
+		base := client.BaseURL()
+		return func(kind, id, _ string) string {
+			return base + "/app/#/" + kind + "/" + url.PathEscape(id) + "/show"
+		}
+	case "musicbrainz":
+		return func(kind, _, name string) string {
+			if kind == "album" {
+				kind = "release_group"
+			}
+			return "https://musicbrainz.org/search?" + url.Values{"query": {name}, "type": {kind}}.Encode()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+// metaListened is the meta key holding all-time listening time in ms.
+const metaListened = "stats.listened_ms"
+
+// countListened adds playback progress since the last call to the session's
+// listening time. It runs after every message, so skips and stops lose at
+// most a tick. Progress is capped by the wall-clock time that passed, so
+// seeking forward doesn't count as listening; pauses and seeks back add
+// nothing.
+func (m *Model) countListened() {
+	if m.player == nil {
+		return
+	}
+	now := time.Now()
+	gen, pos := m.player.Generation(), m.player.Elapsed()
+	if gen != m.listenGen {
+		m.listenGen, m.listenPos = gen, 0
+	}
+	if d := pos - m.listenPos; d > 0 && !m.listenAt.IsZero() {
+		wall := now.Sub(m.listenAt).Seconds() + 1
+		m.listened += time.Duration(min(d, wall) * float64(time.Second))
+	}
+	m.listenPos, m.listenAt = pos, now
+}
+
+// listenedTotal returns all-time listening time, this session included.
+func (m Model) listenedTotal() time.Duration {
+	ms, _ := strconv.ParseInt(m.db.Meta(metaListened), 10, 64)
+	return time.Duration(ms)*time.Millisecond + m.listened - m.listenSaved
+}
+
+// saveListened adds the session's unsaved listening time to the stored
+// all-time total.
+func (m *Model) saveListened() {
+	if m.listened == m.listenSaved {
+		return
+	}
+	total := m.listenedTotal()
+	if err := m.db.SetMeta(metaListened, strconv.FormatInt(total.Milliseconds(), 10)); err != nil {
+		slog.Warn("saving listening time", "err", err)
+		return
+	}
+	m.listenSaved = m.listened
+}
@@ -0,0 +1,154 @@
+package app
+
+import "fmt"
+
+// msgID names a user-facing message. The value is its key in the config's
+// [messages] table, which replaces the English text; a translation keeps
+// the same fmt verbs, in the same order.
+type msgID string
+
+const (
+	msgNothingPlaying   msgID = "nothing_playing"
+	msgCantSeek         msgID = "cant_seek"
+	msgStillBuffering   msgID = "still_buffering"
+	msgEndedEarly       msgID = "ended_early"
+	msgStopped          msgID = "stopped"
+	msgRestart          msgID = "restart"
+	msgSeek             msgID = "seek"
+	msgVolume           msgID = "volume"
+	msgMuted            msgID = "muted"
+	msgShuffled         msgID = "shuffled"
+	msgQueueShuffled    msgID = "queue_shuffled"
+	msgQueueRestored    msgID = "queue_restored"
+	msgFollowOn         msgID = "follow_on"
+	msgFollowOff        msgID = "follow_off"
+	msgPlayingResults   msgID = "playing_results"
+	msgTracksOnly       msgID = "tracks_only"
+	msgAllResults       msgID = "all_results"
+	msgSyncing          msgID = "syncing"
+	msgSynced           msgID = "synced"
+	msgArtistsView      msgID = "artists_view"
+	msgBookmarked       msgID = "bookmarked"
+	msgNoBookmarks      msgID = "no_bookmarks"
+	msgDeleted          msgID = "deleted"
+	msgContinueOn       msgID = "continue_on"
+	msgContinueOff      msgID = "continue_off"
+	msgNoDuplicates     msgID = "no_duplicates"
+	msgNoPlaylists      msgID = "no_playlists"
+	msgAddedToPlaylist  msgID = "added_to_playlist"
+	msgRemovedFrom      msgID = "removed_from_playlist"
+	msgNoPodcasts       msgID = "no_podcasts"
+	msgNoEpisodes       msgID = "no_episodes"
+	msgNoNewEpisodes    msgID = "no_new_episodes"
+	msgNotDownloaded    msgID = "episode_not_downloaded"
+	msgRadioOn          msgID = "radio_on"
+	msgRadioOff         msgID = "radio_off"
+	msgRadioNeedsTrack  msgID = "radio_needs_track"
+	msgNoSimilarSongs   msgID = "no_similar_songs"
+	msgRadioNothingNew  msgID = "radio_nothing_new"
+	msgPanelWidth       msgID = "panel_width"
+	msgNavPanel         msgID = "nav_panel"
+	msgQueuePanel       msgID = "queue_panel"
+	msgPanelWidthsReset msgID = "panel_widths_reset"
+	msgQueueEmpty       msgID = "queue_empty"
+	msgQueueTrimmed     msgID = "queue_trimmed"
+	msgQueueOver        msgID = "queue_over"
+	msgQuitPrompt       msgID = "quit_prompt"
+	msgQuitAgain        msgID = "quit_again"
+	msgSnapshotSaved    msgID = "snapshot_saved"
+	msgNoSnapshots      msgID = "no_snapshots"
+	msgSnapshotGone     msgID = "snapshot_gone"
+	msgSnapshotRestored msgID = "snapshot_restored"
+	msgNoTopSongs       msgID = "no_top_songs"
+	msgTopSongs         msgID = "top_songs"
+	msgMostPlayed       msgID = "most_played"
+	msgUnplayedOnly     msgID = "unplayed_only"
+	msgAllTracks        msgID = "all_tracks"
+	msgRefreshingArt    msgID = "refreshing_art"
+	msgTrack            msgID = "track"
+	msgTracks           msgID = "tracks"
+)
+
+// messages is the English text of every message.
+var messages = map[msgID]string{
+	msgNothingPlaying:   "Nothing playing",
+	msgCantSeek:         "Can't seek this stream",
+	msgStillBuffering:   "Can't seek until the track has downloaded",
+	msgEndedEarly:       "Stream ended early at %s",
+	msgStopped:          "Stopped",
+	msgRestart:          "Restart",
+	msgSeek:             "Seek %s",
+	msgVolume:           "Volume %d%%",
+	msgMuted:            "Muted",
+	msgShuffled:         "Shuffled",
+	msgQueueShuffled:    "Queue shuffled",
+	msgQueueRestored:    "Queue order restored",
+	msgFollowOn:         "Follow on",
+	msgFollowOff:        "Follow off",
+	msgPlayingResults:   "Playing %d results",
+	msgTracksOnly:       "Tracks only",
+	msgAllResults:       "All results",
+	msgSyncing:          "syncing library...",
+	msgSynced:           "%d artists · %d albums · %d tracks",
+	msgArtistsView:      "Artists",
+	msgBookmarked:       "Bookmarked %q",
+	msgNoBookmarks:      "No bookmarks in this track",
+	msgDeleted:          "Deleted %q",
+	msgContinueOn:       "Continue artist on",
+	msgContinueOff:      "Continue artist off",
+	msgNoDuplicates:     "No duplicate tracks",
+	msgNoPlaylists:      "Server has no playlists",
+	msgAddedToPlaylist:  "Added %d %s to %s",
+	msgRemovedFrom:      "Removed from %s",
+	msgNoPodcasts:       "No podcasts on the server",
+	msgNoEpisodes:       "No episodes yet",
+	msgNoNewEpisodes:    "No podcast episodes",
+	msgNotDownloaded:    "Episode isn't downloaded on the server",
+	msgRadioOn:          "Radio on",
+	msgRadioOff:         "Radio off",
+	msgRadioNeedsTrack:  "Play something to start radio",
+	msgNoSimilarSongs:   "Server has no similar songs",
+	msgRadioNothingNew:  "Radio found nothing new",
+	msgPanelWidth:       "%s width %d",
+	msgNavPanel:         "Artists",
+	msgQueuePanel:       "Queue",
+	msgPanelWidthsReset: "Panel widths reset",
+	msgQueueEmpty:       "Queue is empty",
+	msgQueueTrimmed:     "Dropped %d played tracks (queue limit %d)",
+	msgQueueOver:        "Queue is over its %d track limit",
+	msgQuitPrompt:       "Quit? y/n",
+	msgQuitAgain:        "Press q again to quit",
+	msgSnapshotSaved:    "Saved queue as %q",
+	msgNoSnapshots:      "No saved queues",
+	msgSnapshotGone:     "Nothing left of %q in the library",
+	msgSnapshotRestored: "Restored %q",
+	msgNoTopSongs:       "No top songs for %s",
+	msgTopSongs:         "Top songs: %s",
+	msgMostPlayed:       "Most played: %s",
+	msgUnplayedOnly:     "Unplayed tracks only",
+	msgAllTracks:        "All tracks",
+	msgRefreshingArt:    "Refreshing cover art",
+	msgTrack:            "track",
+	msgTracks:           "tracks",
+}
+
+// text returns a message, from the config's [messages] table if it has
+// one, formatted with args.
+func (m Model) text(id msgID, args ...any) string {
+	format, ok := m.cfg.Messages[string(id)]
+	if !ok {
+		format = messages[id]
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// tracksText is "track" or "tracks" to suit n.
+func (m Model) tracksText(n int) string {
+	if n == 1 {
+		return m.text(msgTrack)
+	}
+	return m.text(msgTracks)
+}
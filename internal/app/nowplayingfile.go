@@ -0,0 +1,77 @@
+package app
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// defaultNowPlayingFormat is the now playing file's line when
+// nowplaying_format isn't set.
+const defaultNowPlayingFormat = "{artist} - {title}"
+
+// nowPlayingLine formats the playing track for the now playing file, or
+// returns "" when nothing is playing. Paused tracks are still written, with
+// {state} telling them apart.
+func (m Model) nowPlayingLine() string {
+	cur := m.queue.Current()
+	if cur == nil || m.stopped {
+		return ""
+	}
+	state := "playing"
+	if m.paused {
+		state = "paused"
+	}
+	year := ""
+	if cur.Year > 0 {
+		year = strconv.Itoa(cur.Year)
+	}
+	format := m.cfg.Control.NowPlayingFormat
+	if format == "" {
+		format = defaultNowPlayingFormat
+	}
+	return strings.NewReplacer(
+		"{artist}", cur.Artist,
+		"{title}", cur.Title,
+		"{album}", cur.Album,
+		"{year}", year,
+		"{duration}", formatDuration(cur.DurationMs),
+		"{state}", state,
+	).Replace(format)
+}
+
+// exportNowPlaying rewrites the now playing file when its line changes. It
+// runs after every message, like publishStatus.
+func (m *Model) exportNowPlaying() {
+	if m.cfg.Control.NowPlayingFile == "" {
+		return
+	}
+	line := m.nowPlayingLine()
+	if line == m.nowPlayingOut {
+		return
+	}
+	m.nowPlayingOut = line
+	if err := writeFileAtomic(m.cfg.Control.NowPlayingFile, line); err != nil {
+		slog.Warn("writing now playing file failed", "path", m.cfg.Control.NowPlayingFile, "err", err)
+	}
+}
+
+// writeFileAtomic replaces path with text, so readers polling the file
+// never see it half written.
+func writeFileAtomic(path, text string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // a no-op once renamed
+	if _, err := tmp.WriteString(text); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
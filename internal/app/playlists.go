@@ -0,0 +1,313 @@
+package app
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/simonhull/kitsune/internal/db"
+	"github.com/simonhull/kitsune/internal/subsonic"
+	"github.com/simonhull/kitsune/internal/ui"
+)
+
+// pickerMode is what the picker overlay is currently choosing.
+type pickerMode int
+
+const (
+	pickAddTarget     pickerMode = iota // playlist to add pendingAdd to
+	pickBrowse                          // playlist to open
+	pickPlaylistEntry                   // entry within the open playlist
+	pickAlbum                           // album to play
+	pickDuplicate                       // duplicate track to jump to
+	pickSnapshot                        // queue snapshot to restore
+	pickBookmark                        // bookmark in the current track to jump to
+	pickChannel                         // podcast channel to open
+	pickEpisode                         // podcast episode to play
+)
+
+// playlistsMsg carries the server's playlists for the picker.
+type playlistsMsg struct {
+	mode      pickerMode
+	playlists []subsonic.Playlist
+	err       error
+}
+
+// playlistMsg carries a playlist's entries for browsing.
+type playlistMsg struct {
+	playlist *subsonic.PlaylistDetail
+	err      error
+}
+
+// playlistUpdatedMsg reports the result of changing a playlist.
+type playlistUpdatedMsg struct {
+	id      string
+	name    string
+	added   int
+	removed int
+	err     error
+}
+
+// playlistRemoval is an entry removed from a playlist, by its position.
+type playlistRemoval struct {
+	id, name string
+	idx      int
+}
+
+// fetchPlaylists loads the playlists to pick from in the given mode.
+func (m *Model) fetchPlaylists(mode pickerMode) tea.Cmd {
+	if m.client == nil {
+		return nil
+	}
+	if !m.client.Supports(subsonic.CapPlaylists) {
+		return m.flashOSD(m.text(msgNoPlaylists))
+	}
+	client := m.client
+	return func() tea.Msg {
+		playlists, err := client.GetPlaylists()
+		return playlistsMsg{mode: mode, playlists: playlists, err: err}
+	}
+}
+
+// startAddToPlaylist remembers the songs to add and fetches the playlists
+// to pick from.
+func (m *Model) startAddToPlaylist(songIDs []string) tea.Cmd {
+	if len(songIDs) == 0 {
+		return nil
+	}
+	m.pendingAdd = songIDs
+	return m.fetchPlaylists(pickAddTarget)
+}
+
+// fetchPlaylist loads a playlist's entries for browsing.
+func (m *Model) fetchPlaylist(id string) tea.Cmd {
+	client := m.client
+	return func() tea.Msg {
+		pl, err := client.GetPlaylist(id)
+		return playlistMsg{playlist: pl, err: err}
+	}
+}
+
+// openPlaylistPicker shows the fetched playlists.
+func (m *Model) openPlaylistPicker(msg playlistsMsg) tea.Cmd {
+	if msg.err != nil {
+		m.pendingAdd = nil
+		m.playErr = fmt.Sprintf("playlists: %v", msg.err)
+		return nil
+	}
+
+	items := make([]ui.PickerItem, len(msg.playlists))
+	for i, pl := range msg.playlists {
+		items[i] = ui.PickerItem{ID: pl.ID, Label: pl.Name, Detail: fmt.Sprintf("%d", pl.SongCount)}
+	}
+	title := "Playlists"
+	if msg.mode == pickAddTarget {
+		title = fmt.Sprintf("Add %d %s to playlist", len(m.pendingAdd), m.tracksText(len(m.pendingAdd)))
+	}
+	m.pickerMode = msg.mode
+	m.picker.SetSize(m.width, m.contentHeight())
+	m.picker.Open(title, items)
+	return nil
+}
+
+// openPlaylistEntries shows a playlist's entries, in playlist order so the
+// picker index doubles as the server's song index.
+func (m *Model) openPlaylistEntries(msg playlistMsg) tea.Cmd {
+	if msg.err != nil {
+		m.playErr = fmt.Sprintf("playlist: %v", msg.err)
+		return nil
+	}
+
+	pl := msg.playlist
+	items := make([]ui.PickerItem, len(pl.Entry))
+	for i, s := range pl.Entry {
+		items[i] = ui.PickerItem{ID: s.ID, Label: s.Title, Detail: s.Artist}
+	}
+	m.pickerMode = pickPlaylistEntry
+	m.openPlaylist = pl
+	m.picker.SetSize(m.width, m.contentHeight())
+	m.picker.Open(pl.Name, items)
+	return nil
+}
+
+// updatePicker handles keys while the picker is open.
+func (m *Model) updatePicker(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.picker.Close()
+		m.pendingAdd = nil
+		m.openPlaylist = nil
+		m.dupes = nil
+		m.episodes = nil
+	case "up", "k", "ctrl+p":
+		m.picker.CursorUp()
+	case "down", "j", "ctrl+n":
+		m.picker.CursorDown()
+	case "d":
+		switch m.pickerMode {
+		case pickPlaylistEntry:
+			return *m, m.removePlaylistEntry()
+		case pickSnapshot:
+			return *m, m.deleteSnapshot()
+		case pickBookmark:
+			return *m, m.deleteBookmark()
+		}
+	case "enter":
+		return *m, m.pickerChoose()
+	}
+	return *m, nil
+}
+
+// pickerChoose acts on the highlighted picker item.
+func (m *Model) pickerChoose() tea.Cmd {
+	sel := m.picker.Selected()
+	if sel == nil {
+		return nil
+	}
+
+	switch m.pickerMode {
+	case pickAddTarget:
+		m.picker.Close()
+		client, songIDs, id, name := m.client, m.pendingAdd, sel.ID, sel.Label
+		m.pendingAdd = nil
+		return func() tea.Msg {
+			err := client.UpdatePlaylist(id, songIDs, nil)
+			return playlistUpdatedMsg{id: id, name: name, added: len(songIDs), err: err}
+		}
+
+	case pickBrowse:
+		m.picker.Close()
+		return m.fetchPlaylist(sel.ID)
+
+	case pickAlbum:
+		m.picker.Close()
+		tracks, err := m.db.TracksForAlbum(sel.ID)
+		if err != nil || len(tracks) == 0 {
+			return nil
+		}
+		m.replaceQueue(tracks, 0)
+		return m.playQueueTrack(m.queue.Current())
+
+	case pickSnapshot:
+		m.picker.Close()
+		return m.restoreSnapshot(sel.ID)
+
+	case pickBookmark:
+		return m.jumpToBookmark()
+
+	case pickChannel:
+		m.picker.Close()
+		return m.openChannel(sel.ID, sel.Label)
+
+	case pickEpisode:
+		return m.playEpisode()
+
+	case pickDuplicate:
+		t := m.dupes[m.picker.Cursor()]
+		m.picker.Close()
+		m.dupes = nil
+		m.revealTrack(t)
+		return nil
+
+	case pickPlaylistEntry:
+		// Play the playlist from the chosen entry.
+		idx := m.picker.Cursor()
+		tracks := make([]ui.QueueTrack, len(m.openPlaylist.Entry))
+		for i, s := range m.openPlaylist.Entry {
+			tracks[i] = songQueueTrack(s)
+		}
+		m.picker.Close()
+		m.openPlaylist = nil
+		m.setQueue(tracks, idx)
+		return m.playQueueTrack(m.queue.Current())
+	}
+	return nil
+}
+
+// openDecade lists the albums released in the decade starting at from.
+func (m *Model) openDecade(from int) tea.Cmd {
+	albums, err := m.db.AlbumsByYearRange(from, from+9)
+	if err != nil {
+		m.playErr = fmt.Sprintf("decade: %v", err)
+		return nil
+	}
+	m.openAlbumPicker(fmt.Sprintf("%ds", from), albums)
+	return nil
+}
+
+// openAlbumPicker lists albums to choose one to play.
+func (m *Model) openAlbumPicker(title string, albums []db.AlbumRow) {
+	items := make([]ui.PickerItem, len(albums))
+	for i, a := range albums {
+		detail := a.ArtistName
+		if a.Year > 0 {
+			detail += fmt.Sprintf(" · %d", a.Year)
+		}
+		items[i] = ui.PickerItem{ID: a.ID, Label: a.Name, Detail: detail}
+	}
+	m.pickerMode = pickAlbum
+	m.picker.SetSize(m.width, m.contentHeight())
+	m.picker.Open(title, items)
+}
+
+// removePlaylistEntry deletes the highlighted entry from the open playlist,
+// updating the view before the server confirms. The server removes entries
+// by position, so removals go one at a time, each sent once the one before
+// it is done and the positions after it have shifted.
+func (m *Model) removePlaylistEntry() tea.Cmd {
+	pl := m.openPlaylist
+	idx := m.picker.Cursor()
+	if pl == nil || idx < 0 || idx >= len(pl.Entry) {
+		return nil
+	}
+
+	m.picker.Remove(idx)
+	pl.Entry = append(pl.Entry[:idx], pl.Entry[idx+1:]...)
+
+	m.removals = append(m.removals, playlistRemoval{id: pl.ID, name: pl.Name, idx: idx})
+	if len(m.removals) > 1 {
+		return nil // sent when the ones before it are done
+	}
+	return m.sendRemoval()
+}
+
+// sendRemoval asks the server to drop the first pending playlist removal.
+func (m *Model) sendRemoval() tea.Cmd {
+	client, r := m.client, m.removals[0]
+	return func() tea.Msg {
+		err := client.UpdatePlaylist(r.id, nil, []int{r.idx})
+		return playlistUpdatedMsg{id: r.id, name: r.name, removed: 1, err: err}
+	}
+}
+
+// handlePlaylistUpdated reports the outcome of a playlist change in the
+// status bar, and sends the next pending removal. A failed removal drops
+// the rest and reloads the playlist to undo the optimistic edits.
+func (m *Model) handlePlaylistUpdated(msg playlistUpdatedMsg) tea.Cmd {
+	if msg.removed > 0 && len(m.removals) > 0 {
+		m.removals = m.removals[1:]
+	}
+	if msg.err != nil {
+		m.playErr = fmt.Sprintf("updating %s: %v", msg.name, msg.err)
+		if msg.removed > 0 {
+			m.removals = nil
+			if m.openPlaylist != nil && m.openPlaylist.ID == msg.id {
+				return m.fetchPlaylist(msg.id)
+			}
+		}
+		return nil
+	}
+	if msg.removed > 0 {
+		osd := m.flashOSD(m.text(msgRemovedFrom, msg.name))
+		if len(m.removals) > 0 {
+			return tea.Batch(osd, m.sendRemoval())
+		}
+		return osd
+	}
+	return m.flashOSD(m.text(msgAddedToPlaylist, msg.added, m.tracksText(msg.added), msg.name))
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
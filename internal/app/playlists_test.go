@@ -0,0 +1,62 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/simonhull/kitsune/internal/subsonic"
+)
+
+func TestPlaylistRemovalsGoOneAtATime(t *testing.T) {
+	var mu sync.Mutex
+	var removed []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		removed = append(removed, r.URL.Query()["songIndexToRemove"]...)
+		mu.Unlock()
+		w.Write([]byte(`{"subsonic-response":{"status":"ok"}}`))
+	}))
+	t.Cleanup(srv.Close)
+	client, err := subsonic.NewClient(srv.URL, "alice", "secret", subsonic.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := newTestModel(t)
+	m.client = client
+	m.openPlaylistEntries(playlistMsg{playlist: &subsonic.PlaylistDetail{
+		Playlist: subsonic.Playlist{ID: "pl1", Name: "Mix"},
+		Entry:    []subsonic.Song{{ID: "s1"}, {ID: "s2"}, {ID: "s3"}},
+	}})
+
+	// Two quick presses of d on the first entry: the second waits, as its
+	// position only holds once the first is gone.
+	first := m.removePlaylistEntry()
+	if first == nil {
+		t.Fatal("no command for the first removal")
+	}
+	if cmd := m.removePlaylistEntry(); cmd != nil {
+		t.Fatal("second removal sent while the first is in flight")
+	}
+
+	m.handlePlaylistUpdated(first().(playlistUpdatedMsg))
+	if len(m.removals) != 1 {
+		t.Fatalf("%d removals pending after the first finished, want 1", len(m.removals))
+	}
+	m.handlePlaylistUpdated(m.sendRemoval()().(playlistUpdatedMsg))
+	if len(m.removals) != 0 {
+		t.Errorf("%d removals still pending", len(m.removals))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !slices.Equal(removed, []string{"0", "0"}) {
+		t.Errorf("server removed positions %v, want [0 0]", removed)
+	}
+	if got := m.openPlaylist.Entry; len(got) != 1 || got[0].ID != "s3" {
+		t.Errorf("playlist shows %v, want just s3", got)
+	}
+}
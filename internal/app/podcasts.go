@@ -0,0 +1,157 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/simonhull/kitsune/internal/db"
+	"github.com/simonhull/kitsune/internal/subsonic"
+	"github.com/simonhull/kitsune/internal/ui"
+)
+
+// newestEpisodesCount is how many episodes the newest episodes list shows.
+const newestEpisodesCount = 50
+
+// episodeSaveEvery is how often a playing episode's position is saved.
+// Stopping and quitting save it straight away.
+const episodeSaveEvery = 15 * time.Second
+
+// syncPodcasts refreshes the cached podcasts after a library sync. Servers
+// without podcasts, or failures, leave whatever was cached.
+func (m Model) syncPodcasts() {
+	if !m.client.Supports(subsonic.CapPodcasts) {
+		return
+	}
+	if _, _, err := subsonic.SyncPodcasts(context.Background(), m.client, m.db.Conn, slog.Default()); err != nil {
+		slog.Warn("podcast sync failed", "err", err)
+	}
+}
+
+// openPodcasts lists the podcast channels to browse.
+func (m *Model) openPodcasts() tea.Cmd {
+	channels, err := m.db.PodcastChannels()
+	if err != nil {
+		m.playErr = fmt.Sprintf("podcasts: %v", err)
+		return nil
+	}
+	if len(channels) == 0 {
+		return m.flashOSD(m.text(msgNoPodcasts))
+	}
+	items := make([]ui.PickerItem, len(channels))
+	for i, c := range channels {
+		items[i] = ui.PickerItem{
+			ID:     c.ID,
+			Label:  c.Title,
+			Detail: fmt.Sprintf("%d %s", c.Episodes, plural(c.Episodes, "episode", "episodes")),
+		}
+	}
+	m.pickerMode = pickChannel
+	m.picker.SetSize(m.width, m.contentHeight())
+	m.picker.Open("Podcasts", items)
+	return nil
+}
+
+// openChannel lists a channel's episodes, newest first.
+func (m *Model) openChannel(id, title string) tea.Cmd {
+	episodes, err := m.db.PodcastEpisodes(id)
+	if err != nil {
+		m.playErr = fmt.Sprintf("podcast: %v", err)
+		return nil
+	}
+	if len(episodes) == 0 {
+		return m.flashOSD(m.text(msgNoEpisodes))
+	}
+	m.openEpisodePicker(title, episodes)
+	return nil
+}
+
+// openNewestEpisodes lists the latest episodes across every channel.
+func (m *Model) openNewestEpisodes() tea.Cmd {
+	episodes, err := m.db.NewestEpisodes(newestEpisodesCount)
+	if err != nil {
+		m.playErr = fmt.Sprintf("podcasts: %v", err)
+		return nil
+	}
+	if len(episodes) == 0 {
+		return m.flashOSD(m.text(msgNoNewEpisodes))
+	}
+	m.openEpisodePicker("Newest episodes", episodes)
+	return nil
+}
+
+// openEpisodePicker lists episodes to play, marking ones the server hasn't
+// downloaded and ones part listened to.
+func (m *Model) openEpisodePicker(title string, episodes []db.PodcastEpisode) {
+	items := make([]ui.PickerItem, len(episodes))
+	for i, e := range episodes {
+		detail := e.Published
+		if len(detail) > len("2006-01-02") {
+			detail = detail[:len("2006-01-02")]
+		}
+		switch {
+		case e.StreamID == "":
+			detail += " · " + e.Status
+		case e.Position > 0:
+			detail += " · ▸ " + formatDuration(int(e.Position.Milliseconds())) + " / " + formatDuration(e.DurationMs)
+		default:
+			detail += " · " + formatDuration(e.DurationMs)
+		}
+		items[i] = ui.PickerItem{ID: e.ID, Label: e.Title, Detail: detail}
+	}
+	m.episodes = episodes
+	m.pickerMode = pickEpisode
+	m.picker.SetSize(m.width, m.contentHeight())
+	m.picker.Open(title, items)
+}
+
+// playEpisode plays the highlighted episode on its own, picking up where
+// it was left.
+func (m *Model) playEpisode() tea.Cmd {
+	idx := m.picker.Cursor()
+	if idx < 0 || idx >= len(m.episodes) {
+		return nil
+	}
+	e := m.episodes[idx]
+	if e.StreamID == "" {
+		return m.flashOSD(m.text(msgNotDownloaded))
+	}
+	m.picker.Close()
+	m.episodes = nil
+	m.setQueue([]ui.QueueTrack{episodeQueueTrack(e)}, 0)
+	return m.playQueueTrack(m.queue.Current())
+}
+
+// episodeQueueTrack converts a podcast episode to a queue entry, with the
+// channel standing in for artist and album.
+func episodeQueueTrack(e db.PodcastEpisode) ui.QueueTrack {
+	return ui.QueueTrack{
+		ID:         e.StreamID,
+		Title:      e.Title,
+		Artist:     e.Channel,
+		Album:      e.Channel,
+		DurationMs: e.DurationMs,
+		Format:     e.Format,
+		BitRate:    e.BitRate,
+		CoverArt:   e.CoverArt,
+		Podcast:    true,
+	}
+}
+
+// saveEpisodePosition remembers how far into the playing episode playback
+// is. Unless forced, it saves at most every episodeSaveEvery.
+func (m *Model) saveEpisodePosition(force bool) {
+	cur, pos := m.playingPosition()
+	if cur == nil || !cur.Podcast {
+		return
+	}
+	if !force && time.Since(m.episodeSaved) < episodeSaveEvery {
+		return
+	}
+	m.episodeSaved = time.Now()
+	if err := m.db.SetEpisodePosition(cur.ID, pos); err != nil {
+		slog.Warn("saving episode position failed", "episode", cur.ID, "err", err)
+	}
+}
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/simonhull/kitsune/internal/ui"
+)
+
+func TestEpisodeStartPosition(t *testing.T) {
+	m := newTestModel(t)
+	if _, err := m.db.Conn.Exec(`
+		INSERT INTO podcast_channels (id, title) VALUES ('c1', 'Channel');
+		INSERT INTO podcast_episodes (id, channel_id, stream_id, title, format)
+		VALUES ('e1', 'c1', 's1', 'Episode', 'mp3');
+	`); err != nil {
+		t.Fatalf("inserting episode: %v", err)
+	}
+	if err := m.db.SetEpisodePosition("s1", 12*time.Minute); err != nil {
+		t.Fatalf("SetEpisodePosition: %v", err)
+	}
+	episodes, err := m.db.PodcastEpisodes("c1")
+	if err != nil || len(episodes) != 1 {
+		t.Fatalf("PodcastEpisodes = %v, %v; want one episode", episodes, err)
+	}
+	episode := episodeQueueTrack(episodes[0])
+	track := ui.QueueTrack{ID: "t1", Title: "Track"}
+
+	for _, tc := range []struct {
+		name     string
+		track    *ui.QueueTrack
+		resumeID string
+		resumeAt time.Duration
+		want     time.Duration
+	}{
+		{"episode where listening left off", &episode, "", 0, 12 * time.Minute},
+		{"stopped episode", &episode, "s1", 3 * time.Minute, 3 * time.Minute},
+		{"stopped track", &track, "t1", 90 * time.Second, 90 * time.Second},
+		{"other track", &track, "t2", 90 * time.Second, 0},
+		{"barely started", &track, "t1", 500 * time.Millisecond, 0},
+	} {
+		m.resumeID, m.resumeAt = tc.resumeID, tc.resumeAt
+		if got := m.startPosition(tc.track); got != tc.want {
+			t.Errorf("%s: startPosition = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+
+	// A finished episode starts over.
+	if err := m.db.SetEpisodePosition("s1", 0); err != nil {
+		t.Fatalf("SetEpisodePosition: %v", err)
+	}
+	m.resumeID, m.resumeAt = "", 0
+	if got := m.startPosition(&episode); got != 0 {
+		t.Errorf("finished episode: startPosition = %v, want 0", got)
+	}
+}
+
+func TestEpisodeCoverArt(t *testing.T) {
+	m := newTestModel(t)
+	if _, err := m.db.Conn.Exec(`
+		INSERT INTO podcast_channels (id, title, cover_art) VALUES ('c1', 'Channel', 'pc-c1');
+		INSERT INTO podcast_episodes (id, channel_id, stream_id, title, cover_art)
+		VALUES ('e1', 'c1', 's1', 'Own art', 'pe-e1'), ('e2', 'c1', 's2', 'No art', '');
+	`); err != nil {
+		t.Fatalf("inserting episodes: %v", err)
+	}
+	episodes, err := m.db.PodcastEpisodes("c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"s1": "pe-e1", "s2": "pc-c1"}
+	for _, e := range episodes {
+		track := episodeQueueTrack(e)
+		if got := artKey(&track); got != want[e.StreamID] {
+			t.Errorf("%s: art %q, want %q", e.Title, got, want[e.StreamID])
+		}
+	}
+}
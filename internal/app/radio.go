@@ -0,0 +1,128 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/simonhull/kitsune/internal/subsonic"
+	"github.com/simonhull/kitsune/internal/ui"
+)
+
+const (
+	// radioAhead is how many upcoming tracks radio mode keeps queued.
+	radioAhead = 20
+	// radioFetch is how many similar songs to request per top-up; extra
+	// headroom covers the ones dropped as duplicates.
+	radioFetch = 50
+	// recentLimit bounds the recently played list radio dedupes against.
+	recentLimit = 200
+)
+
+// radioTracksMsg carries similar songs fetched to top up the radio queue.
+type radioTracksMsg struct {
+	tracks []ui.QueueTrack
+	err    error
+}
+
+// toggleRadio turns radio mode on, seeded from the playing track, or off.
+func (m *Model) toggleRadio() tea.Cmd {
+	if m.radio {
+		m.radio = false
+		return m.flashOSD(m.text(msgRadioOff))
+	}
+	if m.client == nil || m.queue.Current() == nil {
+		return m.flashOSD(m.text(msgRadioNeedsTrack))
+	}
+	if !m.client.Supports(subsonic.CapSimilarSongs) {
+		return m.flashOSD(m.text(msgNoSimilarSongs))
+	}
+	m.radio = true
+	m.queue.DropPlayed()
+	return tea.Batch(m.flashOSD(m.text(msgRadioOn)), m.radioTopUp())
+}
+
+// radioTopUp fetches more similar songs when radio mode is short of
+// radioAhead upcoming tracks. Returns nil when nothing needs fetching.
+func (m *Model) radioTopUp() tea.Cmd {
+	seed := m.queue.Current()
+	if !m.radio || m.radioFetching || seed == nil || m.queue.Upcoming() >= radioAhead {
+		return nil
+	}
+	m.radioFetching = true
+
+	// Snapshot what to skip now; the command runs off the update loop.
+	skip := make(map[string]bool, m.queue.Len()+len(m.recent))
+	for _, id := range m.queue.IDs() {
+		skip[id] = true
+	}
+	for _, id := range m.recent {
+		skip[id] = true
+	}
+
+	client, artistID := m.client, seed.ArtistID
+	return func() tea.Msg {
+		songs, err := client.GetSimilarSongs2(artistID, radioFetch)
+		if err != nil {
+			return radioTracksMsg{err: err}
+		}
+		var tracks []ui.QueueTrack
+		for _, s := range songs {
+			if !skip[s.ID] {
+				skip[s.ID] = true
+				tracks = append(tracks, songQueueTrack(s))
+			}
+		}
+		return radioTracksMsg{tracks: tracks}
+	}
+}
+
+// handleRadioTracks appends fetched songs to the radio queue.
+func (m *Model) handleRadioTracks(msg radioTracksMsg) tea.Cmd {
+	m.radioFetching = false
+	if !m.radio {
+		return nil
+	}
+	if msg.err != nil {
+		slog.Warn("radio fetch failed", "err", msg.err)
+		if errors.Is(msg.err, subsonic.ErrNotSupported) {
+			m.radio = false
+		}
+		m.playErr = fmt.Sprintf("radio: %v", msg.err)
+		return nil
+	}
+	if len(msg.tracks) == 0 {
+		return m.flashOSD(m.text(msgRadioNothingNew))
+	}
+
+	need := radioAhead - m.queue.Upcoming()
+	m.queue.Append(msg.tracks[:min(need, len(msg.tracks))]...)
+	m.resizePanels()
+	return nil
+}
+
+// rememberPlayed records a track in the recently played list.
+func (m *Model) rememberPlayed(id string) {
+	m.recent = append(m.recent, id)
+	if len(m.recent) > recentLimit {
+		m.recent = m.recent[len(m.recent)-recentLimit:]
+	}
+}
+
+// songQueueTrack converts a server song to a queue entry.
+func songQueueTrack(s subsonic.Song) ui.QueueTrack {
+	return ui.QueueTrack{
+		ID:         s.ID,
+		Title:      s.Title,
+		Artist:     s.Artist,
+		Album:      s.Album,
+		AlbumID:    s.AlbumID,
+		ArtistID:   s.ArtistID,
+		Year:       s.Year,
+		DurationMs: s.Duration * 1000,
+		Format:     s.Suffix,
+		BitRate:    s.BitRate,
+		CoverArt:   s.CoverArt,
+	}
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"log/slog"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/simonhull/kitsune/internal/config"
+)
+
+// resizeStep is how many columns one resize key press moves a divider.
+const resizeStep = 2
+
+// Narrowest the panels may be dragged.
+const (
+	minNavWidth     = 20
+	minQueueWidth   = 25
+	minContentWidth = 20
+)
+
+// nudgePanel moves the divider beside the nav (queue false) or the queue by
+// delta columns, keeping every panel at its minimum, and saves the new
+// width to the config.
+func (m *Model) nudgePanel(queue bool, delta int) tea.Cmd {
+	navWidth, contentWidth, queueWidth := m.tripleWidths()
+	if queue {
+		delta = -delta // the queue's divider is on its left
+	}
+	room := contentWidth - minContentWidth
+
+	label, cfgKey := m.text(msgNavPanel), "nav_width"
+	width, minWidth := navWidth, minNavWidth
+	if queue {
+		label, cfgKey = m.text(msgQueuePanel), "queue_width"
+		width, minWidth = queueWidth, minQueueWidth
+	}
+	width = max(min(width+delta, width+room), minWidth)
+
+	if queue {
+		m.queueCols = width
+	} else {
+		m.navCols = width
+	}
+	if err := config.SetInt("ui", cfgKey, width); err != nil {
+		slog.Warn("saving panel width", "err", err)
+	}
+	m.resizePanels()
+	return m.flashOSD(m.text(msgPanelWidth, label, width))
+}
+
+// resetPanels goes back to the automatic panel widths.
+func (m *Model) resetPanels() tea.Cmd {
+	m.navCols, m.queueCols = 0, 0
+	for _, k := range []string{"nav_width", "queue_width"} {
+		if err := config.SetInt("ui", k, 0); err != nil {
+			slog.Warn("saving panel width", "err", err)
+		}
+	}
+	m.resizePanels()
+	return m.flashOSD(m.text(msgPanelWidthsReset))
+}
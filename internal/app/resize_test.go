@@ -0,0 +1,55 @@
+package app
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/simonhull/kitsune/internal/config"
+)
+
+func TestNudgePanelsClampAndReset(t *testing.T) {
+	m := newTestModel(t)
+	model, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	m = model.(Model)
+	autoNav, _, autoQueue := m.tripleWidths()
+
+	// Widening the nav stops where the content would drop below its minimum.
+	for range 50 {
+		m.nudgePanel(false, resizeStep)
+	}
+	navWidth, contentWidth, queueWidth := m.tripleWidths()
+	if contentWidth != minContentWidth || navWidth+contentWidth+queueWidth+2 != m.width {
+		t.Errorf("widened nav: widths %d, %d, %d in %d columns", navWidth, contentWidth, queueWidth, m.width)
+	}
+
+	// Narrowing stops at each panel's own minimum. Deltas move the
+	// dividers, so the queue's narrows moving right.
+	for range 50 {
+		m.nudgePanel(false, -resizeStep)
+		m.nudgePanel(true, resizeStep)
+	}
+	if navWidth, _, queueWidth := m.tripleWidths(); navWidth != minNavWidth || queueWidth != minQueueWidth {
+		t.Errorf("narrowed panels to %d and %d, want %d and %d", navWidth, queueWidth, minNavWidth, minQueueWidth)
+	}
+	saved, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.UI.NavWidth != minNavWidth || saved.UI.QueueWidth != minQueueWidth {
+		t.Errorf("config has widths %d and %d, want %d and %d", saved.UI.NavWidth, saved.UI.QueueWidth, minNavWidth, minQueueWidth)
+	}
+
+	m.resetPanels()
+	if navWidth, _, queueWidth := m.tripleWidths(); navWidth != autoNav || queueWidth != autoQueue {
+		t.Errorf("reset widths to %d and %d, want the automatic %d and %d", navWidth, queueWidth, autoNav, autoQueue)
+	}
+	data, err := os.ReadFile(config.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "width") {
+		t.Errorf("config still sets widths after a reset:\n%s", data)
+	}
+}
@@ -0,0 +1,135 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/simonhull/kitsune/internal/db"
+	"github.com/simonhull/kitsune/internal/ui"
+)
+
+// saveSnapshot stores the queue, with the playing track and position, under
+// name. An empty name saves under the current time.
+func (m *Model) saveSnapshot(name string) tea.Cmd {
+	if m.queue.Len() == 0 {
+		return m.flashOSD(m.text(msgQueueEmpty))
+	}
+	if name == "" {
+		name = time.Now().Format("queue 2006-01-02 15:04")
+	}
+
+	current, elapsed := 0, time.Duration(0)
+	if cur := m.queue.Current(); cur != nil {
+		current = m.queue.CurrentIndex()
+		if m.player != nil && !m.stopped {
+			elapsed = time.Duration(m.player.Elapsed() * float64(time.Second))
+		} else if m.resumeID == cur.ID {
+			elapsed = m.resumeAt
+		}
+	}
+	if err := m.db.SaveQueueSnapshot(name, m.queue.IDs(), current, elapsed); err != nil {
+		m.playErr = fmt.Sprintf("saving snapshot: %v", err)
+		return nil
+	}
+	return m.flashOSD(m.text(msgSnapshotSaved, name))
+}
+
+// openSnapshots lists the saved queue snapshots to restore or delete.
+func (m *Model) openSnapshots() tea.Cmd {
+	snapshots, err := m.db.ListQueueSnapshots()
+	if err != nil {
+		m.playErr = fmt.Sprintf("snapshots: %v", err)
+		return nil
+	}
+	if len(snapshots) == 0 {
+		return m.flashOSD(m.text(msgNoSnapshots))
+	}
+
+	items := make([]ui.PickerItem, len(snapshots))
+	for i, s := range snapshots {
+		items[i] = ui.PickerItem{
+			ID:     s.Name,
+			Label:  s.Name,
+			Detail: fmt.Sprintf("%d %s · %s", s.TrackCount, m.tracksText(s.TrackCount), s.SavedAt.Format("Jan 2 "+ui.ClockLayout(m.cfg.UI.Clock))),
+		}
+	}
+	m.pickerMode = pickSnapshot
+	m.picker.SetSize(m.width, m.contentHeight())
+	m.picker.Open("Saved queues", items)
+	return nil
+}
+
+// restoreSnapshot replaces the queue with a snapshot and plays it from the
+// saved track and position.
+func (m *Model) restoreSnapshot(name string) tea.Cmd {
+	s, err := m.db.LoadQueueSnapshot(name)
+	if err != nil {
+		m.playErr = fmt.Sprintf("restoring %s: %v", name, err)
+		return nil
+	}
+	if len(s.Tracks) == 0 {
+		return m.flashOSD(m.text(msgSnapshotGone, name))
+	}
+
+	m.replaceQueue(s.Tracks, s.Current)
+	// Elapsed is zero when the saved track has left the library, so the one
+	// standing in for it starts from the top.
+	cur := m.queue.Current()
+	m.resumeID, m.resumeAt = cur.ID, s.Elapsed
+	return tea.Batch(m.playQueueTrack(cur), m.flashOSD(m.text(msgSnapshotRestored, name)))
+}
+
+// deleteSnapshot removes the highlighted snapshot.
+func (m *Model) deleteSnapshot() tea.Cmd {
+	sel := m.picker.Selected()
+	if sel == nil {
+		return nil
+	}
+	name := sel.ID
+	if err := m.db.DeleteQueueSnapshot(name); err != nil {
+		m.playErr = fmt.Sprintf("deleting snapshot: %v", err)
+		return nil
+	}
+	m.picker.Remove(m.picker.Cursor())
+	return m.flashOSD(m.text(msgDeleted, name))
+}
+
+// saveSession keeps the queue, with the playing track and position, as the
+// session snapshot for the next start.
+func (m *Model) saveSession() {
+	var err error
+	if m.queue.Len() == 0 {
+		err = m.db.DeleteQueueSnapshot(db.SessionSnapshot)
+	} else {
+		_, pos := m.playingPosition()
+		err = m.db.SaveQueueSnapshot(db.SessionSnapshot, m.queue.IDs(), m.queue.CurrentIndex(), pos)
+	}
+	if err != nil {
+		slog.Warn("saving the queue failed", "err", err)
+	}
+}
+
+// restoreSession loads the queue saveSession kept, stopped on the track that
+// was playing so play picks it up from the saved position. A queue built
+// while the library loaded is left alone.
+func (m *Model) restoreSession() {
+	if m.queue.Len() > 0 {
+		return
+	}
+	s, err := m.db.LoadQueueSnapshot(db.SessionSnapshot)
+	if err != nil {
+		if !errors.Is(err, db.ErrNoSnapshot) {
+			slog.Warn("restoring the queue failed", "err", err)
+		}
+		return
+	}
+	if len(s.Tracks) == 0 {
+		return
+	}
+	m.replaceQueue(s.Tracks, s.Current)
+	m.resumeID, m.resumeAt = m.queue.Current().ID, s.Elapsed
+	m.stopped = true
+}
@@ -0,0 +1,47 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/simonhull/kitsune/internal/config"
+	"github.com/simonhull/kitsune/internal/db"
+)
+
+func TestSessionResumesAfterRestart(t *testing.T) {
+	m := newTestModel(t)
+	seedLibrary(t, m, testAlbum{
+		AlbumRow: db.AlbumRow{ID: "al1", Name: "Music for Airports", ArtistID: "ar1", ArtistName: "Brian Eno"},
+		Tracks: []db.TrackRow{
+			{ID: "t1", Title: "1/1", TrackNum: 1},
+			{ID: "t2", Title: "2/1", TrackNum: 2},
+		},
+	})
+	tracks, err := m.db.TracksForAlbum("al1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.replaceQueue(tracks, 1)
+	m.stopped = true
+	m.resumeID, m.resumeAt = "t2", 90*time.Second
+	m.quit()
+
+	next := New(config.Default(), m.db, nil, nil, nil)
+	next.applyStartup()
+	if got := next.queue.IDs(); len(got) != 2 {
+		t.Fatalf("restored queue %v, want both tracks", got)
+	}
+	cur, pos := next.playingPosition()
+	if cur == nil || cur.ID != "t2" || pos != 90*time.Second || !next.stopped {
+		t.Errorf("restored stopped %v at %v %v, want stopped at t2 90s", next.stopped, cur, pos)
+	}
+
+	// An empty queue at quit leaves nothing to restore.
+	next.queue.Replace(nil, 0)
+	next.quit()
+	last := New(config.Default(), m.db, nil, nil, nil)
+	last.applyStartup()
+	if last.queue.Len() != 0 {
+		t.Errorf("restored %d tracks after quitting with an empty queue", last.queue.Len())
+	}
+}
@@ -0,0 +1,176 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/simonhull/kitsune/internal/ui"
+)
+
+// metaUIState is the meta key holding the session's uiState, restored at
+// startup.
+const metaUIState = "ui.state"
+
+// metaLastArtist is where older versions kept only the artist filter; it's
+// still read when no uiState has been saved yet.
+const metaLastArtist = "ui.last_artist"
+
+// recentAlbumsCount is how many albums the "recent" startup view lists.
+const recentAlbumsCount = 50
+
+// uiState is where the user was when the app last quit. Cursors are kept
+// as the IDs of the items under them, so they find the same items again
+// after a sync has moved them.
+type uiState struct {
+	Focus  string `json:"focus"` // "artists", "content", or "queue", as in start_focus
+	Artist string `json:"artist,omitempty"`
+	// NavArtist is the artist under the nav cursor.
+	NavArtist string `json:"nav_artist,omitempty"`
+	// The content row under the cursor: a track, album or artist header.
+	ContentTrack  string `json:"content_track,omitempty"`
+	ContentAlbum  string `json:"content_album,omitempty"`
+	ContentArtist string `json:"content_artist,omitempty"`
+	// QueueTrack is the track under the queue cursor.
+	QueueTrack string `json:"queue_track,omitempty"`
+	// Recent is the recently played list, oldest first.
+	Recent []string `json:"recent,omitempty"`
+}
+
+// applyStartup sets the configured initial focus and view once the library
+// is first loaded. Later syncs leave the user's place alone.
+func (m *Model) applyStartup() {
+	if m.started {
+		return
+	}
+	m.started = true
+
+	// New set the configured focus; keep it, or wherever the user moved it
+	// while syncing, on the panels the sync just built.
+	m.setFocus(m.focus)
+	m.restoreSession()
+	m.restoreUIState()
+
+	switch m.cfg.UI.StartView {
+	case "recent":
+		albums, err := m.db.RecentAlbums(recentAlbumsCount)
+		if err != nil {
+			m.playErr = fmt.Sprintf("recent albums: %v", err)
+			return
+		}
+		if len(albums) > 0 {
+			m.openAlbumPicker("Recently added", albums)
+		}
+	}
+}
+
+// focusNamed returns the panel named as in start_focus: "artists" (or
+// "nav"), "content", or "queue", defaulting to the content browser.
+func focusNamed(name string) focus {
+	switch name {
+	case "artists", "nav":
+		return focusArtistNav
+	case "queue":
+		return focusQueue
+	default:
+		return focusContent
+	}
+}
+
+// focusName is the start_focus name of the focused panel.
+func (m *Model) focusName() string {
+	switch m.focus {
+	case focusArtistNav:
+		return "artists"
+	case focusQueue:
+		return "queue"
+	default:
+		return "content"
+	}
+}
+
+// restoreUIState puts the recently played list and the artist list and
+// queue cursors back as saveUIState left them and, with the "last" start view, the focus, artist filter and
+// content cursor too. Anything that has since left the library or the
+// queue is skipped, leaving that cursor where it was.
+func (m *Model) restoreUIState() {
+	if m.content == nil {
+		return
+	}
+	var st uiState
+	if raw := m.db.Meta(metaUIState); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &st); err != nil {
+			slog.Warn("ignoring saved ui state", "err", err)
+			return
+		}
+	} else {
+		st.Artist = m.db.Meta(metaLastArtist)
+	}
+
+	// Tracks played while the library loaded follow the saved ones.
+	m.recent = append(st.Recent, m.recent...)
+	m.recent = m.recent[max(len(m.recent)-recentLimit, 0):]
+	last := m.cfg.UI.StartView == "last"
+	if last && st.Artist != "" && m.nav != nil {
+		m.nav.SelectByID(st.Artist)
+		if m.nav.SelectedID() == st.Artist {
+			m.content.FilterByArtist(st.Artist)
+		}
+	}
+	if m.nav != nil && st.NavArtist != "" {
+		m.nav.MoveToID(st.NavArtist)
+	}
+	if last {
+		switch {
+		case st.ContentTrack != "":
+			m.content.ScrollToTrack(st.ContentTrack)
+		case st.ContentAlbum != "":
+			m.content.ScrollToAlbum(st.ContentAlbum)
+		case st.ContentArtist != "":
+			m.content.ScrollToArtist(st.ContentArtist)
+		}
+	}
+	if i := slices.Index(m.queue.IDs(), st.QueueTrack); i >= 0 {
+		m.queue.SetCursor(i)
+	}
+	if last && st.Focus != "" {
+		m.setFocus(focusNamed(st.Focus))
+	}
+}
+
+// saveUIState remembers the focus, artist filter, panel cursors and
+// recently played tracks for the next start.
+func (m *Model) saveUIState() {
+	if m.content == nil {
+		return
+	}
+	st := uiState{
+		Focus:  m.focusName(),
+		Artist: m.content.FilterArtistID(),
+		Recent: m.recent,
+	}
+	if m.nav != nil {
+		st.NavArtist = m.nav.CursorID()
+	}
+	if row := m.content.CursorRow(); row != nil {
+		switch row.Kind {
+		case ui.ContentTrack:
+			st.ContentTrack = row.TrackID
+		case ui.ContentAlbum:
+			st.ContentAlbum = row.AlbumID
+		case ui.ContentArtist:
+			st.ContentArtist = row.ArtistID
+		}
+	}
+	if t := m.queue.Selected(); t != nil {
+		st.QueueTrack = t.ID
+	}
+	raw, err := json.Marshal(st)
+	if err == nil {
+		err = m.db.SetMeta(metaUIState, string(raw))
+	}
+	if err != nil {
+		slog.Warn("saving ui state failed", "err", err)
+	}
+}
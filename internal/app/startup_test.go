@@ -0,0 +1,70 @@
+package app
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/simonhull/kitsune/internal/config"
+	"github.com/simonhull/kitsune/internal/db"
+	"github.com/simonhull/kitsune/internal/subsonic"
+	"github.com/simonhull/kitsune/internal/ui"
+)
+
+func TestRestoreUIState(t *testing.T) {
+	m := newTestModel(t)
+	seedLibrary(t, m,
+		testAlbum{
+			AlbumRow: db.AlbumRow{ID: "al1", Name: "Kid A", ArtistID: "ar1", ArtistName: "Radiohead"},
+			Tracks:   []db.TrackRow{{ID: "a1", TrackNum: 1}, {ID: "a2", TrackNum: 2}},
+		},
+		testAlbum{
+			AlbumRow: db.AlbumRow{ID: "al2", Name: "Secret Name", ArtistID: "ar2", ArtistName: "Low"},
+			Tracks:   []db.TrackRow{{ID: "b1", TrackNum: 1}, {ID: "b2", TrackNum: 2}},
+		},
+	)
+	model, _ := m.Update(syncDoneMsg{&subsonic.SyncResult{}})
+	m = model.(Model)
+	m.nav.MoveToID("ar1")
+	m.content.ScrollToTrack("b2")
+	tracks, err := m.db.TracksForAlbum("al1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.replaceQueue(tracks, 0)
+	m.queue.SetCursor(1)
+	m.setFocus(focusQueue)
+	m.rememberPlayed("a1")
+	m.quit()
+
+	// A sync adds an artist that sorts first, moving every row down.
+	seedLibrary(t, m, testAlbum{
+		AlbumRow: db.AlbumRow{ID: "al3", Name: "To Each...", ArtistID: "ar3", ArtistName: "A Certain Ratio"},
+		Tracks:   []db.TrackRow{{ID: "c1", TrackNum: 1}},
+	})
+
+	for _, view := range []string{"last", "all"} {
+		cfg := config.Default()
+		cfg.UI.StartView = view
+		next := New(cfg, m.db, nil, nil, nil)
+		model, _ := next.Update(syncDoneMsg{&subsonic.SyncResult{}})
+		next = model.(Model)
+
+		if !slices.Equal(next.recent, []string{"a1"}) {
+			t.Errorf("%s: recently played %v, want [a1]", view, next.recent)
+		}
+		if got := next.nav.CursorID(); got != "ar1" {
+			t.Errorf("%s: nav cursor on %q, want ar1", view, got)
+		}
+		if sel := next.queue.Selected(); sel == nil || sel.ID != "a2" {
+			t.Errorf("%s: queue cursor on %v, want a2", view, sel)
+		}
+		row := next.content.CursorRow()
+		onB2 := row != nil && row.Kind == ui.ContentTrack && row.TrackID == "b2"
+		if onB2 != (view == "last") {
+			t.Errorf("%s: content cursor on %+v", view, row)
+		}
+		if (next.focus == focusQueue) != (view == "last") {
+			t.Errorf("%s: focus %v", view, next.focus)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package app
+
+import (
+	"log/slog"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/term"
+)
+
+// sizeWait is how long to wait for the terminal to report its size before
+// asking the TTY directly. Some multiplexers never send the first resize.
+const sizeWait = 500 * time.Millisecond
+
+// fallbackWidth and fallbackHeight are assumed when the TTY can't say.
+const (
+	fallbackWidth  = 80
+	fallbackHeight = 24
+)
+
+// sizeCheckMsg fires sizeWait after startup to check a size has arrived.
+type sizeCheckMsg struct{}
+
+func sizeCheck() tea.Cmd {
+	return tea.Tick(sizeWait, func(time.Time) tea.Msg { return sizeCheckMsg{} })
+}
+
+// terminalSize asks the TTY for its size, falling back to 80×24.
+func terminalSize() tea.WindowSizeMsg {
+	for _, f := range []*os.File{os.Stdout, os.Stdin} {
+		if w, h, err := term.GetSize(f.Fd()); err == nil && w > 0 && h > 0 {
+			return tea.WindowSizeMsg{Width: w, Height: h}
+		}
+	}
+	slog.Warn("terminal size unknown, assuming defaults", "width", fallbackWidth, "height", fallbackHeight)
+	return tea.WindowSizeMsg{Width: fallbackWidth, Height: fallbackHeight}
+}
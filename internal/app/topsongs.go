@@ -0,0 +1,93 @@
+package app
+
+import (
+	"errors"
+	"log/slog"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/simonhull/kitsune/internal/db"
+	"github.com/simonhull/kitsune/internal/subsonic"
+	"github.com/simonhull/kitsune/internal/ui"
+)
+
+// topSongsCount is how many top songs to request for an artist.
+const topSongsCount = 50
+
+// topSongsMsg carries an artist's top songs, or the fallback if the server
+// couldn't provide them.
+type topSongsMsg struct {
+	artistID   string
+	artistName string
+	tracks     []ui.QueueTrack
+	fallback   bool
+	err        error
+}
+
+// playTopSongs queues and plays an artist's most popular tracks. Results are
+// cached per artist for the session.
+func (m *Model) playTopSongs(artistID, artistName string) tea.Cmd {
+	if artistID == "" || artistName == "" {
+		return nil
+	}
+	if tracks, ok := m.topSongs[artistID]; ok {
+		return m.startTopSongs(artistName, tracks, false)
+	}
+	if m.client == nil || !m.client.Supports(subsonic.CapTopSongs) {
+		return m.startTopSongs(artistName, m.topSongsFallback(artistID), true)
+	}
+
+	client := m.client
+	return func() tea.Msg {
+		songs, err := client.GetTopSongs(artistName, topSongsCount)
+		msg := topSongsMsg{artistID: artistID, artistName: artistName, err: err}
+		for _, s := range songs {
+			msg.tracks = append(msg.tracks, songQueueTrack(s))
+		}
+		return msg
+	}
+}
+
+// handleTopSongs plays fetched top songs, falling back to the local library
+// when the server doesn't implement getTopSongs or knows nothing about the
+// artist (last.fm names don't always match library names).
+func (m *Model) handleTopSongs(msg topSongsMsg) tea.Cmd {
+	if msg.err != nil && !errors.Is(msg.err, subsonic.ErrNotSupported) {
+		slog.Warn("top songs fetch failed", "artist", msg.artistName, "err", msg.err)
+	}
+	if msg.err != nil || len(msg.tracks) == 0 {
+		return m.startTopSongs(msg.artistName, m.topSongsFallback(msg.artistID), true)
+	}
+	m.topSongs[msg.artistID] = msg.tracks
+	return m.startTopSongs(msg.artistName, msg.tracks, false)
+}
+
+// startTopSongs replaces the queue with tracks and starts playing.
+func (m *Model) startTopSongs(artistName string, tracks []ui.QueueTrack, fallback bool) tea.Cmd {
+	if len(tracks) == 0 {
+		return m.flashOSD(m.text(msgNoTopSongs, artistName))
+	}
+	m.setQueue(slices.Clone(tracks), 0)
+	label := m.text(msgTopSongs, artistName)
+	if fallback {
+		label = m.text(msgMostPlayed, artistName)
+	}
+	return tea.Batch(m.playQueueTrack(m.queue.Current()), m.flashOSD(label))
+}
+
+// topSongsFallback ranks an artist's library tracks by how often they
+// appear among the recently played, keeping album order for ties.
+func (m *Model) topSongsFallback(artistID string) []ui.QueueTrack {
+	tracks, err := m.db.TracksForArtist(artistID)
+	if err != nil {
+		return nil
+	}
+	plays := make(map[string]int)
+	for _, id := range m.recent {
+		plays[id]++
+	}
+	slices.SortStableFunc(tracks, func(a, b db.TrackRow) int {
+		return plays[b.ID] - plays[a.ID]
+	})
+	return toQueueTracks(tracks[:min(len(tracks), topSongsCount)])
+}
@@ -5,8 +5,11 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
+	"github.com/simonhull/kitsune/internal/control"
+	"github.com/simonhull/kitsune/internal/player"
 	"github.com/simonhull/kitsune/internal/ui"
 )
 
@@ -15,7 +18,13 @@ type Config struct {
 	Subsonic SubsonicConfig `toml:"subsonic"`
 	Library  LibraryConfig  `toml:"library"`
 	UI       UIConfig       `toml:"ui"`
+	Playback PlaybackConfig `toml:"playback"`
+	Player   player.Config  `toml:"player"`
 	Theme    ui.ThemeConfig `toml:"theme"`
+	Control  control.Config `toml:"control"`
+	// Messages replaces the app's status messages by key, e.g.
+	// stopped = "Arrêté", keeping each message's %d/%s/%q in order.
+	Messages map[string]string `toml:"messages"`
 }
 
 // SubsonicConfig configures the Subsonic server connection.
@@ -23,6 +32,16 @@ type SubsonicConfig struct {
 	URL      string `toml:"url"`
 	Username string `toml:"username"`
 	Password string `toml:"password"`
+	// TimeoutSec bounds each API request. Audio streams are exempt.
+	TimeoutSec int `toml:"timeout_sec"`
+	// CACert is a PEM file of extra certificates to trust (self-signed proxies).
+	CACert string `toml:"ca_cert"`
+	// Proxy is an HTTP(S) proxy URL; empty falls back to HTTP(S)_PROXY.
+	Proxy string `toml:"proxy"`
+	// InsecureSkipVerify disables TLS verification. Last resort only.
+	InsecureSkipVerify bool `toml:"insecure_skip_verify"`
+	// Debug logs every request (credentials redacted). KITSUNE_DEBUG=1 also enables it.
+	Debug bool `toml:"debug"`
 }
 
 // LibraryConfig configures local music sources (optional).
@@ -32,15 +51,177 @@ type LibraryConfig struct {
 
 // UIConfig configures the user interface.
 type UIConfig struct {
+	// AlbumArt selects the art backend: auto, kitty, iterm2, sixel, blocks
+	// (truecolor half-blocks), ascii (plain characters), or off.
 	AlbumArt string `toml:"album_art"`
+	// TickMs is how often the now playing position refreshes while playing.
+	// The tick stops entirely while paused.
+	TickMs int `toml:"tick_ms"`
+	// FrameMs redraws the seek bar this often between ticks, moving it on
+	// smoothly from the last position read; 0 only redraws on ticks.
+	FrameMs int `toml:"frame_ms"`
+	// SearchMinChars is how many characters the palette needs before searching.
+	SearchMinChars int `toml:"search_min_chars"`
+	// SearchLimit is how many matching tracks the palette lists before
+	// loading more as you scroll.
+	SearchLimit int `toml:"search_limit"`
+	// SearchExactAccents makes search tell accented letters apart, so
+	// "bjork" no longer finds "Björk". Search always ignores case.
+	SearchExactAccents bool `toml:"search_exact_accents"`
+	// SearchKinds restricts palette results to "artist", "album" and/or
+	// "track"; empty means all three.
+	SearchKinds []string `toml:"search_kinds"`
+	// TrackOrder sorts tracks within an album: "track" (number) or "title".
+	TrackOrder string `toml:"track_order"`
+	// QueueFollow keeps the playing track in view: "visible", "center", or "off".
+	QueueFollow string `toml:"queue_follow"`
+	// QueueFollowIdleSec is how long after manual queue scrolling follow resumes.
+	QueueFollowIdleSec int `toml:"queue_follow_idle_sec"`
+	// Space sets what the space bar does: "pause" only toggles pause, "smart"
+	// also plays the selected item when nothing is playing.
+	Space string `toml:"space"`
+	// ArtistSeparators leaves a blank row between artists in the full library view.
+	ArtistSeparators bool `toml:"artist_separators"`
+	// NavWidth and QueueWidth fix the artist list and queue widths in
+	// columns; 0 gives them a share of the terminal's width. alt+h/l and
+	// alt+H/L set them from the app, and alt+0 puts them back to 0.
+	NavWidth   int `toml:"nav_width"`
+	QueueWidth int `toml:"queue_width"`
+	// TimeRemaining shows time left ("-1:23") instead of the total on the seek
+	// bar. Clicking the time, or e, toggles it until the app quits.
+	TimeRemaining bool `toml:"time_remaining"`
+	// ElapsedOnly shows just the time played beside the seek bar, without
+	// the total or remaining time.
+	ElapsedOnly bool `toml:"elapsed_only"`
+	// Clock is how times of day are shown, as for the last sync and saved
+	// queues: "24h" ("15:04") or "12h" ("3:04 PM").
+	Clock string `toml:"clock"`
+	// ProgressBar is the seek bar style: "line", "block" (with part-filled
+	// cells), or "braille" (the same, in dots).
+	ProgressBar string `toml:"progressbar"`
+	// ProgressFilled and ProgressEmpty replace the style's played and unplayed
+	// glyphs; each must be a single-width character.
+	ProgressFilled string `toml:"progress_filled"`
+	ProgressEmpty  string `toml:"progress_empty"`
+	// Hyperlinks makes artist and album names clickable in terminals with
+	// OSC 8 support: "server" opens them in the server's web UI (Navidrome),
+	// "musicbrainz" searches MusicBrainz, "off" leaves them plain.
+	Hyperlinks string `toml:"hyperlinks"`
+	// Visualizer draws a row of frequency band levels under the seek bar,
+	// refreshed every tick_ms. It adds a little work per audio sample.
+	Visualizer bool `toml:"visualizer"`
+	// Background paints the whole app on the theme's dim background color
+	// instead of the terminal's own, which clashes with transparent
+	// terminals, hence off by default.
+	Background bool `toml:"background"`
+	// ProgressGradient shades the played part of the seek bar from the
+	// accent color to a lighter tint. Needs a truecolor terminal; others keep
+	// the solid accent.
+	ProgressGradient bool `toml:"progress_gradient"`
+	// ArtBorder frames the now playing album art with a rounded border.
+	ArtBorder bool `toml:"art_border"`
+	// ArtPosition puts the now playing art "left" or "right" of the track
+	// info, or "center" to drop the art and center the text.
+	ArtPosition string `toml:"art_position"`
+	// StartFocus is the panel focused at startup: "artists" (or "nav"),
+	// "content", or "queue".
+	StartFocus string `toml:"start_focus"`
+	// StartView is what the browser shows at startup: "all" content,
+	// "recent" (a list of recently added albums), or "last" (the focus,
+	// artist filter and content cursor in use when the app last quit). The
+	// artist list and queue cursors are put back whatever the view.
+	StartView string `toml:"start_view"`
+	// AlbumSort is the order the albums view (v) opens in: "artist", "year",
+	// "recent" (added), or "played" (most plays). V cycles through them.
+	AlbumSort string `toml:"album_sort"`
+	// PaletteSections groups palette results under "Artists", "Albums" and
+	// "Tracks" headers with their counts.
+	PaletteSections bool `toml:"palette_sections"`
+	// Dense fits more on screen: palette results take one line instead of
+	// two and now playing shrinks to a title line and the seek bar.
+	Dense bool `toml:"dense"`
+	// ConfirmQuit makes q (or ctrl+c) ask to be pressed again within a couple
+	// of seconds before quitting. While a track is loaded the prompt reads
+	// "Quit? y/n" and y quits too.
+	ConfirmQuit bool `toml:"confirm_quit"`
+}
+
+// PlaybackConfig configures queue and playback behavior.
+type PlaybackConfig struct {
+	// MaxQueue caps the queue length; already-played tracks are trimmed to fit,
+	// with a notice when they are or when upcoming tracks alone exceed it
+	// (0 = unlimited).
+	MaxQueue int `toml:"max_queue"`
+	// OnSelect is what choosing an artist, album or track in the browser does
+	// to the queue: "replace" it, "append" and play, or "artist" to replace
+	// when the choice is by the artist the queue was last filled from and
+	// append otherwise.
+	OnSelect string `toml:"on_select"`
+	// ContinueArtist starts with artist continuation on (n toggles it): when
+	// a queue holding only one artist's albums runs out, their next album is
+	// queued and played.
+	ContinueArtist bool `toml:"continue_artist"`
+	// FallbackFormat is the format to ask the server to transcode to when a
+	// track's original file can't be decoded, at the start or part way
+	// through; "" gives up straight away.
+	FallbackFormat string `toml:"fallback_format"`
+	// SeekStep is how far [ and ] seek, e.g. "5s".
+	SeekStep Duration `toml:"seek_step"`
+	// SeekStepLarge is how far { and } (or shift+arrow) seek, e.g. "30s".
+	SeekStepLarge Duration `toml:"seek_step_large"`
+}
+
+// Duration is a time.Duration written in config as a string like "5s" or "1m30s".
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalText parses a duration string.
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %w", text, err)
+	}
+	d.Duration = v
+	return nil
+}
+
+// MarshalText formats the duration as a string.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
 }
 
 // Default returns a config with sensible defaults.
 func Default() Config {
 	return Config{
+		Subsonic: SubsonicConfig{
+			TimeoutSec: 30,
+		},
 		UI: UIConfig{
-			AlbumArt: "auto",
+			AlbumArt:           "auto",
+			TickMs:             500,
+			FrameMs:            100,
+			SearchMinChars:     2,
+			SearchLimit:        50,
+			TrackOrder:         "track",
+			QueueFollow:        "visible",
+			QueueFollowIdleSec: 10,
+			Space:              "pause",
+			ArtPosition:        "left",
+			StartFocus:         "content",
+			StartView:          "all",
+			AlbumSort:          "artist",
+			Clock:              "24h",
+			ArtistSeparators:   true,
+		},
+		Playback: PlaybackConfig{
+			MaxQueue:       1000,
+			OnSelect:       "replace",
+			FallbackFormat: "mp3",
+			SeekStep:       Duration{5 * time.Second},
+			SeekStepLarge:  Duration{30 * time.Second},
 		},
+		Player: player.DefaultConfig(),
 	}
 }
 
@@ -75,10 +256,109 @@ func Load() (Config, error) {
 	}
 
 	cfg.Library.Path = expandHome(cfg.Library.Path)
+	cfg.Subsonic.CACert = expandHome(cfg.Subsonic.CACert)
+	cfg.Control.NowPlayingFile = expandHome(cfg.Control.NowPlayingFile)
+	if cfg.Subsonic.TimeoutSec <= 0 {
+		cfg.Subsonic.TimeoutSec = Default().Subsonic.TimeoutSec
+	}
+	if cfg.UI.TickMs <= 0 {
+		cfg.UI.TickMs = Default().UI.TickMs
+	}
+	if cfg.UI.FrameMs < 0 || cfg.UI.FrameMs >= cfg.UI.TickMs {
+		cfg.UI.FrameMs = 0
+	}
+	if cfg.UI.SearchLimit <= 0 {
+		cfg.UI.SearchLimit = Default().UI.SearchLimit
+	}
+	if cfg.UI.SearchMinChars <= 0 {
+		cfg.UI.SearchMinChars = 1
+	}
+	if cfg.UI.QueueFollowIdleSec < 0 {
+		cfg.UI.QueueFollowIdleSec = 0
+	}
+	if cfg.Playback.SeekStep.Duration <= 0 {
+		cfg.Playback.SeekStep = Default().Playback.SeekStep
+	}
+	if cfg.Playback.SeekStepLarge.Duration <= 0 {
+		cfg.Playback.SeekStepLarge = Default().Playback.SeekStepLarge
+	}
+	if cfg.Player.PrebufferMs < 0 {
+		cfg.Player.PrebufferMs = 0
+	}
+	if cfg.Player.ResumeRewindSec < 0 {
+		cfg.Player.ResumeRewindSec = 0
+	}
+	if cfg.Player.SeekBufferMB < 0 {
+		cfg.Player.SeekBufferMB = 0
+	}
 
 	return cfg, nil
 }
 
+// SetInt stores key = n in the config file's [section] table, leaving the
+// rest of the file as it is. n = 0 removes the key, leaving the default.
+func SetInt(section, key string, n int) error {
+	data, err := os.ReadFile(Path())
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading config: %w", err)
+	}
+	text := setInt(string(data), section, key, n)
+	if err := os.MkdirAll(Dir(), 0o755); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+	// Write beside the file and rename, so a crash can't leave half a config.
+	tmp := Path() + ".tmp"
+	if err := os.WriteFile(tmp, []byte(text), 0o600); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+	return os.Rename(tmp, Path())
+}
+
+// setInt is SetInt on the file's text.
+func setInt(text, section, key string, n int) string {
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	if text == "" {
+		lines = nil
+	}
+	value := fmt.Sprintf("%s = %d", key, n)
+
+	header := -1
+	current := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			current = strings.TrimSpace(strings.Trim(trimmed, "[]"))
+			if current == section {
+				header = i
+			}
+			continue
+		}
+		name, _, ok := strings.Cut(trimmed, "=")
+		if current != section || !ok || strings.TrimSpace(name) != key {
+			continue
+		}
+		if n == 0 {
+			lines = append(lines[:i], lines[i+1:]...)
+		} else {
+			lines[i] = value
+		}
+		return strings.Join(lines, "\n") + "\n"
+	}
+
+	switch {
+	case n == 0:
+		return text
+	case header >= 0:
+		lines = append(lines[:header+1], append([]string{value}, lines[header+1:]...)...)
+	default:
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, "["+section+"]", value)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
 // HasSubsonic reports whether a Subsonic server is configured.
 func (c Config) HasSubsonic() bool {
 	return c.Subsonic.URL != "" && c.Subsonic.Username != ""
@@ -0,0 +1,22 @@
+package config
+
+import "testing"
+
+func TestSetInt(t *testing.T) {
+	for _, tc := range []struct {
+		name, text string
+		n          int
+		want       string
+	}{
+		{"empty file", "", 30, "[ui]\nnav_width = 30\n"},
+		{"new table", "[subsonic]\nurl = \"x\"\n", 30, "[subsonic]\nurl = \"x\"\n\n[ui]\nnav_width = 30\n"},
+		{"new key", "[ui]\n# wide art\nart_border = true\n", 30, "[ui]\nnav_width = 30\n# wide art\nart_border = true\n"},
+		{"replaced", "[ui]\nnav_width = 24\n[playback]\nnav_width = 1\n", 30, "[ui]\nnav_width = 30\n[playback]\nnav_width = 1\n"},
+		{"removed", "[ui]\nnav_width = 24\ndense = true\n", 0, "[ui]\ndense = true\n"},
+		{"nothing to remove", "[ui]\ndense = true\n", 0, "[ui]\ndense = true\n"},
+	} {
+		if got := setInt(tc.text, "ui", "nav_width", tc.n); got != tc.want {
+			t.Errorf("%s: got\n%q\nwant\n%q", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+// Package control serves kitsune's playback status and accepts playback
+// commands over HTTP, for status bars and scripts.
+//
+// Without a token anything that can reach the server's address can read
+// what's playing and control playback. It binds to 127.0.0.1 unless the
+// configured address names another host; only do that on a trusted network,
+// and preferably with a token. Requests must name the server's own address
+// as their Host, so web pages can't reach it through a rebinding domain, and
+// commands must be sent as JSON, which pages can't do without the server's
+// say-so.
+package control
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config configures the control server.
+type Config struct {
+	// HTTPAddr enables the HTTP server, e.g. "127.0.0.1:7700" or just "7700".
+	// A bare port or ":port" binds to 127.0.0.1. Empty disables it.
+	HTTPAddr string `toml:"http_addr"`
+	// Token, if set, must be sent with every request as
+	// "Authorization: Bearer <token>".
+	Token string `toml:"token"`
+	// NowPlayingFile is a file kept holding the playing track, e.g. for a
+	// streaming overlay. It's emptied when playback stops and on quit.
+	NowPlayingFile string `toml:"nowplaying_file"`
+	// NowPlayingFormat is the file's line, with {artist}, {title}, {album},
+	// {year}, {duration} and {state} (playing or paused) filled in. The
+	// default is "{artist} - {title}".
+	NowPlayingFormat string `toml:"nowplaying_format"`
+}
+
+// Actions are the commands POST /command accepts.
+var Actions = []string{"play", "pause", "toggle", "stop", "next", "prev", "restart"}
+
+// CommandMsg delivers a command to the app's update loop.
+type CommandMsg struct {
+	Action string
+}
+
+// Track describes the current track in a Status.
+type Track struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	Artist     string `json:"artist"`
+	Album      string `json:"album"`
+	DurationMs int    `json:"duration_ms"`
+}
+
+// Status is the playback state served by GET /status.
+type Status struct {
+	State       string  `json:"state"` // "playing", "paused" or "stopped"
+	Track       *Track  `json:"track"`
+	PositionSec float64 `json:"position_sec"`
+	QueueLength int     `json:"queue_length"`
+	QueueIndex  int     `json:"queue_index"` // zero-based, -1 when nothing is current
+}
+
+// Server is the HTTP control server. The app publishes its status with
+// SetStatus; commands are handed to send, typically tea.Program.Send.
+type Server struct {
+	addr    string
+	token   string
+	elapsed func() float64
+	send    func(any)
+	logger  *slog.Logger
+
+	mu     sync.Mutex
+	status Status
+	srv    *http.Server
+}
+
+// NewServer creates a control server. elapsed reports the playing track's
+// position when a status is requested, so it's current between updates.
+func NewServer(cfg Config, elapsed func() float64, send func(any), logger *slog.Logger) *Server {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Server{
+		addr:    localAddr(cfg.HTTPAddr),
+		token:   cfg.Token,
+		elapsed: elapsed,
+		send:    send,
+		logger:  logger.With("component", "control"),
+		status:  Status{State: "stopped", QueueIndex: -1},
+	}
+}
+
+// localAddr defaults the host of addr to 127.0.0.1.
+func localAddr(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		// A bare port.
+		return net.JoinHostPort("127.0.0.1", addr)
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// Start listens on the configured address and serves in the background.
+func (s *Server) Start() error {
+	ln, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	if _, port, _ := net.SplitHostPort(s.addr); port == "0" {
+		// Requests name the port actually picked.
+		s.addr = ln.Addr().String()
+	}
+	s.srv = &http.Server{Handler: s.handler(), ReadHeaderTimeout: 5 * time.Second}
+	srv := s.srv
+	s.mu.Unlock()
+
+	s.logger.Info("control server listening", "addr", ln.Addr().String())
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Warn("control server stopped", "err", err)
+		}
+	}()
+	return nil
+}
+
+// handler routes requests, turning away any that fail guard.
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", s.handleStatus)
+	mux.HandleFunc("POST /command", s.handleCommand)
+	return s.guard(mux)
+}
+
+// guard rejects requests that don't name the server's address as their
+// Host, or that lack the token when one is configured.
+func (s *Server) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.allowedHost(r.Host) {
+			http.Error(w, "unrecognized host", http.StatusForbidden)
+			return
+		}
+		if s.token != "" {
+			got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) != 1 {
+				http.Error(w, "missing or wrong token", http.StatusUnauthorized)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allowedHost reports whether a request's Host names the address the server
+// is bound to. Browsers send the name they looked up, so a page on a domain
+// rebound to 127.0.0.1 still sends its own name and is turned away.
+func (s *Server) allowedHost(hostport string) bool {
+	s.mu.Lock()
+	addr := s.addr
+	s.mu.Unlock()
+
+	host, port, err := net.SplitHostPort(hostport)
+	if err != nil {
+		return false
+	}
+	boundHost, boundPort, _ := net.SplitHostPort(addr)
+	if port != boundPort {
+		return false
+	}
+	if strings.EqualFold(host, boundHost) {
+		return true
+	}
+	bound := net.ParseIP(boundHost)
+	if bound == nil {
+		return false
+	}
+	if strings.EqualFold(host, "localhost") {
+		return bound.IsLoopback() || bound.IsUnspecified()
+	}
+	// Bound to every interface, any of its addresses will do.
+	ip := net.ParseIP(host)
+	return ip != nil && (ip.Equal(bound) || bound.IsUnspecified())
+}
+
+// Close shuts the server down.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	srv := s.srv
+	s.mu.Unlock()
+	if srv == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return srv.Shutdown(ctx)
+}
+
+// SetStatus publishes the app's current status.
+func (s *Server) SetStatus(status Status) {
+	s.mu.Lock()
+	s.status = status
+	s.mu.Unlock()
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	status := s.status
+	s.mu.Unlock()
+	if status.State != "stopped" && s.elapsed != nil {
+		status.PositionSec = s.elapsed()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+func (s *Server) handleCommand(w http.ResponseWriter, r *http.Request) {
+	// Forms and other simple requests a page can send anywhere never carry
+	// a JSON content type.
+	if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt != "application/json" {
+		http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var cmd struct {
+		Action string `json:"action"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1024)).Decode(&cmd); err != nil {
+		http.Error(w, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if !slices.Contains(Actions, cmd.Action) {
+		http.Error(w, "unknown action", http.StatusBadRequest)
+		return
+	}
+
+	s.send(CommandMsg{Action: cmd.Action})
+	w.WriteHeader(http.StatusAccepted)
+}
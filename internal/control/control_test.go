@@ -0,0 +1,88 @@
+package control
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGuard(t *testing.T) {
+	var sent []any
+	s := NewServer(Config{HTTPAddr: "7700"}, nil, func(msg any) { sent = append(sent, msg) }, nil)
+	h := s.handler()
+
+	for _, tc := range []struct {
+		name        string
+		method      string
+		path        string
+		host        string
+		contentType string
+		want        int
+	}{
+		{"status", "GET", "/status", "127.0.0.1:7700", "", http.StatusOK},
+		{"status as localhost", "GET", "/status", "localhost:7700", "", http.StatusOK},
+		{"status from a rebound domain", "GET", "/status", "evil.example:7700", "", http.StatusForbidden},
+		{"status on another port", "GET", "/status", "127.0.0.1:80", "", http.StatusForbidden},
+		{"command", "POST", "/command", "127.0.0.1:7700", "application/json", http.StatusAccepted},
+		{"command with charset", "POST", "/command", "127.0.0.1:7700", "application/json; charset=utf-8", http.StatusAccepted},
+		{"command as a form", "POST", "/command", "127.0.0.1:7700", "text/plain", http.StatusUnsupportedMediaType},
+		{"command without a type", "POST", "/command", "127.0.0.1:7700", "", http.StatusUnsupportedMediaType},
+		{"command from a rebound domain", "POST", "/command", "evil.example:7700", "application/json", http.StatusForbidden},
+	} {
+		req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(`{"action":"toggle"}`))
+		req.Host = tc.host
+		if tc.contentType != "" {
+			req.Header.Set("Content-Type", tc.contentType)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Errorf("%s: status %d, want %d", tc.name, rec.Code, tc.want)
+		}
+	}
+	if len(sent) != 2 {
+		t.Errorf("sent %d commands, want 2", len(sent))
+	}
+}
+
+func TestGuardToken(t *testing.T) {
+	s := NewServer(Config{HTTPAddr: "7700", Token: "secret"}, nil, func(any) {}, nil)
+	h := s.handler()
+
+	for _, tc := range []struct {
+		auth string
+		want int
+	}{
+		{"Bearer secret", http.StatusOK},
+		{"Bearer wrong", http.StatusUnauthorized},
+		{"secret", http.StatusUnauthorized},
+		{"", http.StatusUnauthorized},
+	} {
+		req := httptest.NewRequest("GET", "/status", nil)
+		req.Host = "127.0.0.1:7700"
+		if tc.auth != "" {
+			req.Header.Set("Authorization", tc.auth)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Errorf("Authorization %q: status %d, want %d", tc.auth, rec.Code, tc.want)
+		}
+	}
+}
+
+func TestAllowedHostUnspecified(t *testing.T) {
+	s := NewServer(Config{HTTPAddr: "0.0.0.0:7700"}, nil, nil, nil)
+	for host, want := range map[string]bool{
+		"192.168.1.20:7700": true,
+		"localhost:7700":    true,
+		"[::1]:7700":        true,
+		"music.lan:7700":    false,
+		"192.168.1.20":      false,
+	} {
+		if got := s.allowedHost(host); got != want {
+			t.Errorf("allowedHost(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package db
+
+import "time"
+
+// Bookmark is a named position within a track.
+type Bookmark struct {
+	ID       int64
+	TrackID  string
+	Name     string
+	Position time.Duration
+}
+
+// AddBookmark stores a named position within a track.
+func (db *DB) AddBookmark(trackID, name string, pos time.Duration) error {
+	_, err := db.Conn.Exec(`
+		INSERT INTO bookmarks (track_id, name, position_ms, created_at) VALUES (?, ?, ?, ?)
+	`, trackID, name, pos.Milliseconds(), time.Now().Unix())
+	return err
+}
+
+// Bookmarks returns a track's bookmarks in position order.
+func (db *DB) Bookmarks(trackID string) ([]Bookmark, error) {
+	rows, err := db.Conn.Query(`
+		SELECT id, track_id, name, position_ms FROM bookmarks
+		WHERE track_id = ? ORDER BY position_ms
+	`, trackID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var marks []Bookmark
+	for rows.Next() {
+		var b Bookmark
+		var posMs int64
+		if err := rows.Scan(&b.ID, &b.TrackID, &b.Name, &posMs); err != nil {
+			return nil, err
+		}
+		b.Position = time.Duration(posMs) * time.Millisecond
+		marks = append(marks, b)
+	}
+	return marks, rows.Err()
+}
+
+// DeleteBookmark removes a bookmark.
+func (db *DB) DeleteBookmark(id int64) error {
+	_, err := db.Conn.Exec(`DELETE FROM bookmarks WHERE id = ?`, id)
+	return err
+}
@@ -6,14 +6,17 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"time"
 
 	_ "modernc.org/sqlite"
 )
 
 // DB wraps the SQLite database for the music library cache.
 type DB struct {
-	Conn   *sql.DB
-	logger *slog.Logger
+	Conn       *sql.DB
+	path       string
+	logger     *slog.Logger
+	trackOrder TrackOrder
 }
 
 // Open opens or creates the library database with WAL mode enabled.
@@ -34,8 +37,10 @@ func Open(logger *slog.Logger) (*DB, error) {
 	}
 
 	db := &DB{
-		Conn:   conn,
-		logger: logger.With("component", "db"),
+		Conn:       conn,
+		path:       dbPath,
+		logger:     logger.With("component", "db"),
+		trackOrder: TrackOrderNumber,
 	}
 
 	if err := db.migrate(); err != nil {
@@ -47,6 +52,14 @@ func Open(logger *slog.Logger) (*DB, error) {
 	return db, nil
 }
 
+// SetTrackOrder sets how tracks are ordered within an album.
+func (db *DB) SetTrackOrder(order TrackOrder) {
+	if order != TrackOrderTitle {
+		order = TrackOrderNumber
+	}
+	db.trackOrder = order
+}
+
 // Close closes the database connection.
 func (db *DB) Close() error {
 	return db.Conn.Close()
@@ -82,16 +95,69 @@ func (db *DB) AlbumCount() int {
 	return count
 }
 
-const currentVersion = 2
+// TotalDuration returns the combined length of every track in the library.
+func (db *DB) TotalDuration() time.Duration {
+	var ms int64
+	db.Conn.QueryRow("SELECT COALESCE(SUM(duration_ms), 0) FROM tracks").Scan(&ms)
+	return time.Duration(ms) * time.Millisecond
+}
+
+// Size returns the database file size in bytes, or 0 if it can't be read.
+func (db *DB) Size() int64 {
+	info, err := os.Stat(db.path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// LastSyncTime returns when the library was last synced, or the zero time if never.
+func (db *DB) LastSyncTime() time.Time {
+	var unix int64
+	if err := db.Conn.QueryRow("SELECT value FROM meta WHERE key = 'last_sync'").Scan(&unix); err != nil {
+		return time.Time{}
+	}
+	return time.Unix(unix, 0)
+}
+
+// SetLastSyncTime records when the library was last synced.
+func (db *DB) SetLastSyncTime(t time.Time) error {
+	_, err := db.Conn.Exec(`
+		INSERT INTO meta (key, value) VALUES ('last_sync', ?)
+		ON CONFLICT(key) DO UPDATE SET value=excluded.value
+	`, t.Unix())
+	return err
+}
+
+// Meta returns a stored value from the meta table, or "" if unset.
+func (db *DB) Meta(key string) string {
+	var value string
+	db.Conn.QueryRow("SELECT value FROM meta WHERE key = ?", key).Scan(&value)
+	return value
+}
+
+// SetMeta stores a value in the meta table.
+func (db *DB) SetMeta(key, value string) error {
+	_, err := db.Conn.Exec(`
+		INSERT INTO meta (key, value) VALUES (?, ?)
+		ON CONFLICT(key) DO UPDATE SET value=excluded.value
+	`, key, value)
+	return err
+}
+
+const currentVersion = 10
 
 // migrate runs schema migrations using PRAGMA user_version.
 func (db *DB) migrate() error {
 	var version int
 	db.Conn.QueryRow("PRAGMA user_version").Scan(&version)
 
-	if version < currentVersion {
-		db.logger.Info("migrating database", "from", version, "to", currentVersion)
+	if version >= currentVersion {
+		return nil
+	}
+	db.logger.Info("migrating database", "from", version, "to", currentVersion)
 
+	if version < 2 {
 		// Drop old v1 schema (local-only tracks table).
 		if _, err := db.Conn.Exec(dropV1); err != nil {
 			return fmt.Errorf("dropping v1 schema: %w", err)
@@ -101,12 +167,61 @@ func (db *DB) migrate() error {
 		if _, err := db.Conn.Exec(schemaV2); err != nil {
 			return fmt.Errorf("creating v2 schema: %w", err)
 		}
+	}
+
+	if version < 3 {
+		if _, err := db.Conn.Exec(schemaV3); err != nil {
+			return fmt.Errorf("creating v3 schema: %w", err)
+		}
+	}
+
+	if version < 4 {
+		if _, err := db.Conn.Exec(schemaV4); err != nil {
+			return fmt.Errorf("creating v4 schema: %w", err)
+		}
+	}
+
+	if version < 5 {
+		if _, err := db.Conn.Exec(schemaV5); err != nil {
+			return fmt.Errorf("creating v5 schema: %w", err)
+		}
+	}
 
-		if _, err := db.Conn.Exec(fmt.Sprintf("PRAGMA user_version = %d", currentVersion)); err != nil {
-			return fmt.Errorf("setting schema version: %w", err)
+	if version < 6 {
+		if _, err := db.Conn.Exec(schemaV6); err != nil {
+			return fmt.Errorf("creating v6 schema: %w", err)
 		}
 	}
 
+	if version < 7 {
+		// Re-index search so accents are ignored by default.
+		if err := db.rebuildFTS(ftsFolded); err != nil {
+			return err
+		}
+	}
+
+	if version < 8 {
+		if _, err := db.Conn.Exec(schemaV8); err != nil {
+			return fmt.Errorf("creating v8 schema: %w", err)
+		}
+	}
+
+	if version < 9 {
+		if _, err := db.Conn.Exec(schemaV9); err != nil {
+			return fmt.Errorf("creating v9 schema: %w", err)
+		}
+	}
+
+	if version < 10 {
+		if _, err := db.Conn.Exec(schemaV10); err != nil {
+			return fmt.Errorf("creating v10 schema: %w", err)
+		}
+	}
+
+	if _, err := db.Conn.Exec(fmt.Sprintf("PRAGMA user_version = %d", currentVersion)); err != nil {
+		return fmt.Errorf("setting schema version: %w", err)
+	}
+
 	return nil
 }
 
@@ -190,3 +305,91 @@ CREATE TRIGGER IF NOT EXISTS tracks_fts_update AFTER UPDATE ON tracks BEGIN
 	VALUES (new.rowid, new.title, new.artist, new.album);
 END;
 `
+
+var schemaV3 = `
+-- Key/value store for library-wide state (last sync time, etc.).
+CREATE TABLE IF NOT EXISTS meta (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+`
+
+var schemaV4 = `
+-- When the server added each album, for "recently added".
+ALTER TABLE albums ADD COLUMN created TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_albums_created ON albums(created);
+`
+
+var schemaV5 = `
+-- Named queue snapshots, saved and restored locally.
+CREATE TABLE IF NOT EXISTS queue_snapshots (
+	name       TEXT PRIMARY KEY,
+	current    INTEGER NOT NULL DEFAULT 0,
+	elapsed_ms INTEGER NOT NULL DEFAULT 0,
+	saved_at   INTEGER NOT NULL -- unix seconds
+);
+
+CREATE TABLE IF NOT EXISTS queue_snapshot_tracks (
+	name     TEXT NOT NULL,
+	position INTEGER NOT NULL,
+	track_id TEXT NOT NULL,
+	PRIMARY KEY (name, position),
+	FOREIGN KEY (name) REFERENCES queue_snapshots(name)
+);
+`
+
+var schemaV6 = `
+-- Named positions within tracks, for jumping around long mixes.
+CREATE TABLE IF NOT EXISTS bookmarks (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	track_id    TEXT NOT NULL,
+	name        TEXT NOT NULL,
+	position_ms INTEGER NOT NULL,
+	created_at  INTEGER NOT NULL -- unix seconds
+);
+
+CREATE INDEX IF NOT EXISTS idx_bookmarks_track ON bookmarks(track_id, position_ms);
+`
+
+var schemaV8 = `
+-- Listening stats, imported or kept locally (preserved across syncs).
+ALTER TABLE tracks ADD COLUMN rating INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE tracks ADD COLUMN play_count INTEGER NOT NULL DEFAULT 0;
+`
+
+var schemaV9 = `
+-- Podcasts the server subscribes to, synced after the library.
+CREATE TABLE IF NOT EXISTS podcast_channels (
+	id          TEXT PRIMARY KEY,
+	title       TEXT NOT NULL DEFAULT '',
+	description TEXT NOT NULL DEFAULT '',
+	cover_art   TEXT NOT NULL DEFAULT '',
+	status      TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS podcast_episodes (
+	id          TEXT PRIMARY KEY,
+	channel_id  TEXT NOT NULL,
+	stream_id   TEXT NOT NULL DEFAULT '', -- empty until the server downloads it
+	title       TEXT NOT NULL DEFAULT '',
+	description TEXT NOT NULL DEFAULT '',
+	published   TEXT NOT NULL DEFAULT '',
+	status      TEXT NOT NULL DEFAULT '',
+	duration_ms INTEGER NOT NULL DEFAULT 0,
+	format      TEXT NOT NULL DEFAULT '',
+	bitrate     INTEGER NOT NULL DEFAULT 0,
+	cover_art   TEXT NOT NULL DEFAULT '',
+	-- kitsune-specific (preserved across syncs)
+	position_ms INTEGER NOT NULL DEFAULT 0,
+	FOREIGN KEY (channel_id) REFERENCES podcast_channels(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_podcast_episodes_channel ON podcast_episodes(channel_id, published);
+CREATE INDEX IF NOT EXISTS idx_podcast_episodes_stream ON podcast_episodes(stream_id);
+`
+
+var schemaV10 = `
+-- Entries a sync left out of each album, like videos and folders, so an
+-- album holding some still matches the server's song count next time.
+ALTER TABLE albums ADD COLUMN non_audio INTEGER NOT NULL DEFAULT 0;
+`
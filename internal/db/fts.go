@@ -0,0 +1,85 @@
+package db
+
+import "fmt"
+
+// Tokenizers for tracks_fts. unicode61 always folds case; remove_diacritics
+// decides whether "bjork" finds "Björk".
+const (
+	ftsFolded = "unicode61 remove_diacritics 2"
+	ftsExact  = "unicode61 remove_diacritics 0"
+)
+
+// metaFTSTokenizer is the meta key recording the tokenizer tracks_fts was
+// built with.
+const metaFTSTokenizer = "search.tokenizer"
+
+// SetAccentFolding sets whether search ignores accents, rebuilding the
+// search index if it was built the other way.
+func (db *DB) SetAccentFolding(fold bool) error {
+	want := ftsExact
+	if fold {
+		want = ftsFolded
+	}
+	if db.Meta(metaFTSTokenizer) == want {
+		return nil
+	}
+	db.logger.Info("rebuilding search index", "tokenizer", want)
+	return db.rebuildFTS(want)
+}
+
+// rebuildFTS recreates tracks_fts and its sync triggers with the given
+// tokenizer and re-indexes every track.
+func (db *DB) rebuildFTS(tokenizer string) error {
+	tx, err := db.Conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmts := []string{
+		`DROP TRIGGER IF EXISTS tracks_fts_insert`,
+		`DROP TRIGGER IF EXISTS tracks_fts_delete`,
+		`DROP TRIGGER IF EXISTS tracks_fts_update`,
+		`DROP TABLE IF EXISTS tracks_fts`,
+		fmt.Sprintf(`CREATE VIRTUAL TABLE tracks_fts USING fts5(
+			title, artist, album,
+			content='tracks',
+			content_rowid='rowid',
+			tokenize='%s'
+		)`, tokenizer),
+		ftsTriggers,
+		`INSERT INTO tracks_fts(tracks_fts) VALUES ('rebuild')`,
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("rebuilding search index: %w", err)
+		}
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO meta (key, value) VALUES (?, ?)
+		ON CONFLICT(key) DO UPDATE SET value=excluded.value
+	`, metaFTSTokenizer, tokenizer); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ftsTriggers keep tracks_fts in step with the tracks table.
+const ftsTriggers = `
+CREATE TRIGGER tracks_fts_insert AFTER INSERT ON tracks BEGIN
+	INSERT INTO tracks_fts(rowid, title, artist, album)
+	VALUES (new.rowid, new.title, new.artist, new.album);
+END;
+
+CREATE TRIGGER tracks_fts_delete AFTER DELETE ON tracks BEGIN
+	INSERT INTO tracks_fts(tracks_fts, rowid, title, artist, album)
+	VALUES ('delete', old.rowid, old.title, old.artist, old.album);
+END;
+
+CREATE TRIGGER tracks_fts_update AFTER UPDATE ON tracks BEGIN
+	INSERT INTO tracks_fts(tracks_fts, rowid, title, artist, album)
+	VALUES ('delete', old.rowid, old.title, old.artist, old.album);
+	INSERT INTO tracks_fts(rowid, title, artist, album)
+	VALUES (new.rowid, new.title, new.artist, new.album);
+END;
+`
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"log/slog"
+	"testing"
+)
+
+// openTestDB opens a fresh database under a temporary data directory.
+func openTestDB(t *testing.T) *DB {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	db, err := Open(slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// addTrack inserts a track, and its album, by artist.
+func addTrack(t *testing.T, db *DB, id, title, artist string) {
+	t.Helper()
+	if _, err := db.Conn.Exec(`INSERT OR IGNORE INTO albums (id, name, artist_id, artist_name) VALUES ('al1', 'Debut', 'ar1', ?)`, artist); err != nil {
+		t.Fatalf("inserting album: %v", err)
+	}
+	if _, err := db.Conn.Exec(`INSERT INTO tracks (id, title, artist, album, album_id, artist_id) VALUES (?, ?, ?, 'Debut', 'al1', 'ar1')`,
+		id, title, artist); err != nil {
+		t.Fatalf("inserting track: %v", err)
+	}
+}
+
+// searchTracks returns the IDs of the tracks matching query.
+func searchTracks(t *testing.T, db *DB, query string) []string {
+	t.Helper()
+	results, err := db.Search(query, 10, []string{"track"})
+	if err != nil {
+		t.Fatalf("Search(%q): %v", query, err)
+	}
+	var ids []string
+	for _, r := range results {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func TestAccentFolding(t *testing.T) {
+	db := openTestDB(t)
+	addTrack(t, db, "t1", "Human Behaviour", "Björk")
+
+	if err := db.SetAccentFolding(true); err != nil {
+		t.Fatalf("SetAccentFolding(true): %v", err)
+	}
+	if got := searchTracks(t, db, "bjork"); len(got) != 1 {
+		t.Errorf("folded search for bjork = %v, want t1", got)
+	}
+
+	if err := db.SetAccentFolding(false); err != nil {
+		t.Fatalf("SetAccentFolding(false): %v", err)
+	}
+	if got := searchTracks(t, db, "bjork"); len(got) != 0 {
+		t.Errorf("exact search for bjork = %v, want nothing", got)
+	}
+	if got := searchTracks(t, db, "björk"); len(got) != 1 {
+		t.Errorf("exact search for björk = %v, want t1", got)
+	}
+	if got := db.Meta(metaFTSTokenizer); got != ftsExact {
+		t.Errorf("tokenizer recorded as %q, want %q", got, ftsExact)
+	}
+
+	// Tracks synced after a rebuild are indexed by the recreated triggers.
+	if err := db.SetAccentFolding(true); err != nil {
+		t.Fatalf("SetAccentFolding(true) again: %v", err)
+	}
+	addTrack(t, db, "t2", "Jóga", "Björk")
+	if got := searchTracks(t, db, "joga"); len(got) != 1 || got[0] != "t2" {
+		t.Errorf("folded search for joga = %v, want t2", got)
+	}
+	if got := searchTracks(t, db, "bjork"); len(got) != 2 {
+		t.Errorf("folded search for bjork = %v, want both tracks", got)
+	}
+}
@@ -0,0 +1,107 @@
+package db
+
+import "time"
+
+// PodcastChannel is a cached podcast channel.
+type PodcastChannel struct {
+	ID       string
+	Title    string
+	CoverArt string
+	Episodes int // episodes the server has downloaded
+}
+
+// PodcastEpisode is a cached podcast episode.
+type PodcastEpisode struct {
+	ID         string
+	ChannelID  string
+	Channel    string // the channel's title
+	StreamID   string // "" until the server has downloaded it
+	Title      string
+	Published  string // ISO 8601
+	Status     string
+	DurationMs int
+	Format     string
+	BitRate    int           // kbps
+	CoverArt   string        // the episode's own art, else the channel's
+	Position   time.Duration // where listening left off
+}
+
+// PodcastChannels returns every cached channel, by title.
+func (db *DB) PodcastChannels() ([]PodcastChannel, error) {
+	rows, err := db.Conn.Query(`
+		SELECT c.id, c.title, c.cover_art,
+			(SELECT COUNT(*) FROM podcast_episodes e WHERE e.channel_id = c.id AND e.stream_id != '')
+		FROM podcast_channels c
+		ORDER BY c.title COLLATE NOCASE
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var channels []PodcastChannel
+	for rows.Next() {
+		var c PodcastChannel
+		if err := rows.Scan(&c.ID, &c.Title, &c.CoverArt, &c.Episodes); err != nil {
+			return nil, err
+		}
+		channels = append(channels, c)
+	}
+	return channels, rows.Err()
+}
+
+// episodeSelect is the column list scanned by queryEpisodes.
+const episodeSelect = `
+	SELECT e.id, e.channel_id, c.title, e.stream_id, e.title, e.published, e.status,
+		e.duration_ms, e.format, e.bitrate, COALESCE(NULLIF(e.cover_art, ''), c.cover_art), e.position_ms
+	FROM podcast_episodes e
+	JOIN podcast_channels c ON e.channel_id = c.id
+`
+
+// PodcastEpisodes returns a channel's episodes, newest first.
+func (db *DB) PodcastEpisodes(channelID string) ([]PodcastEpisode, error) {
+	return db.queryEpisodes(`WHERE e.channel_id = ? ORDER BY e.published DESC`, channelID)
+}
+
+// NewestEpisodes returns up to limit downloaded episodes across every
+// channel, newest first.
+func (db *DB) NewestEpisodes(limit int) ([]PodcastEpisode, error) {
+	return db.queryEpisodes(`WHERE e.stream_id != '' ORDER BY e.published DESC LIMIT ?`, limit)
+}
+
+func (db *DB) queryEpisodes(clause string, args ...any) ([]PodcastEpisode, error) {
+	rows, err := db.Conn.Query(episodeSelect+clause, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var episodes []PodcastEpisode
+	for rows.Next() {
+		var e PodcastEpisode
+		var posMs int64
+		if err := rows.Scan(&e.ID, &e.ChannelID, &e.Channel, &e.StreamID, &e.Title, &e.Published, &e.Status,
+			&e.DurationMs, &e.Format, &e.BitRate, &e.CoverArt, &posMs); err != nil {
+			return nil, err
+		}
+		e.Position = time.Duration(posMs) * time.Millisecond
+		episodes = append(episodes, e)
+	}
+	return episodes, rows.Err()
+}
+
+// EpisodePosition returns where listening left off in the episode streamed
+// as streamID, or 0.
+func (db *DB) EpisodePosition(streamID string) time.Duration {
+	var posMs int64
+	db.Conn.QueryRow(`SELECT position_ms FROM podcast_episodes WHERE stream_id = ?`, streamID).Scan(&posMs)
+	return time.Duration(posMs) * time.Millisecond
+}
+
+// SetEpisodePosition records where listening left off in the episode
+// streamed as streamID; 0 marks it as finished or unstarted.
+func (db *DB) SetEpisodePosition(streamID string, pos time.Duration) error {
+	_, err := db.Conn.Exec(`UPDATE podcast_episodes SET position_ms = ? WHERE stream_id = ?`,
+		pos.Milliseconds(), streamID)
+	return err
+}
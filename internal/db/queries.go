@@ -1,5 +1,37 @@
 package db
 
+import (
+	"cmp"
+	"database/sql"
+	"slices"
+	"strings"
+	"time"
+)
+
+// TrackOrder controls how tracks are ordered within an album.
+type TrackOrder string
+
+const (
+	// TrackOrderNumber sorts by disc and track number, falling back to title
+	// for albums whose track numbers are all zero.
+	TrackOrderNumber TrackOrder = "track"
+	// TrackOrderTitle always sorts by disc, then title.
+	TrackOrderTitle TrackOrder = "title"
+)
+
+// AlbumSort orders the flat list of all albums.
+type AlbumSort string
+
+const (
+	AlbumSortArtist AlbumSort = "artist" // by artist, then year
+	AlbumSortYear   AlbumSort = "year"   // oldest first, then artist
+	AlbumSortRecent AlbumSort = "recent" // most recently added first
+	AlbumSortPlayed AlbumSort = "played" // most plays across the album's tracks first
+)
+
+// AlbumSorts lists every album order, in the order the albums view cycles.
+var AlbumSorts = []AlbumSort{AlbumSortArtist, AlbumSortYear, AlbumSortRecent, AlbumSortPlayed}
+
 // ArtistRow is a single artist from the library.
 type ArtistRow struct {
 	ID         string
@@ -12,6 +44,7 @@ type AlbumRow struct {
 	ID         string
 	Name       string
 	ArtistID   string
+	ArtistName string
 	Year       int
 	SongCount  int
 	DurationMs int
@@ -25,14 +58,19 @@ type TrackRow struct {
 	Artist         string
 	Album          string
 	AlbumID        string
+	ArtistID       string
 	TrackNum       int
 	DiscNum        int
 	DurationMs     int
 	Year           int
 	Genre          string
 	Format         string
+	BitRate        int // kbps
 	ShuffleExclude bool
 	LinkedNextID   string
+	Rating         int // 0 unrated, else 1-5
+	PlayCount      int
+	CoverArt       string // the server's cover art ID, "" if it gave none
 }
 
 // AllArtists returns all artists, sorted alphabetically by name.
@@ -58,10 +96,66 @@ func (db *DB) AllArtists() ([]ArtistRow, error) {
 
 // AlbumsForArtist returns all albums for an artist, sorted by year then name.
 func (db *DB) AlbumsForArtist(artistID string) ([]AlbumRow, error) {
+	return db.queryAlbums(`WHERE artist_id = ? ORDER BY year, name COLLATE NOCASE`, artistID)
+}
+
+// AlbumsByYearRange returns albums released from one year to another,
+// inclusive, sorted by year then artist. Albums with an unknown year (0)
+// are only included when the range starts at 0.
+func (db *DB) AlbumsByYearRange(from, to int) ([]AlbumRow, error) {
+	return db.queryAlbums(`
+		WHERE year BETWEEN ? AND ?
+		ORDER BY year, artist_name COLLATE NOCASE, name COLLATE NOCASE
+	`, from, to)
+}
+
+// RecentAlbums returns up to limit albums, most recently added first. Albums
+// the server gave no creation date for are left out.
+func (db *DB) RecentAlbums(limit int) ([]AlbumRow, error) {
+	return db.queryAlbums(`WHERE created != '' ORDER BY created DESC LIMIT ?`, limit)
+}
+
+// AllAlbums returns every album in the given order. Unknown orders sort by
+// artist.
+func (db *DB) AllAlbums(sort AlbumSort) ([]AlbumRow, error) {
+	switch sort {
+	case AlbumSortYear:
+		return db.queryAlbums(`ORDER BY year, artist_name COLLATE NOCASE, name COLLATE NOCASE`)
+	case AlbumSortRecent:
+		// Albums the server gave no creation date for go last.
+		return db.queryAlbums(`ORDER BY created = '', created DESC, name COLLATE NOCASE`)
+	case AlbumSortPlayed:
+		return db.queryAlbums(`
+			ORDER BY (SELECT COALESCE(SUM(t.play_count), 0) FROM tracks t WHERE t.album_id = albums.id) DESC,
+				artist_name COLLATE NOCASE, year, name COLLATE NOCASE
+		`)
+	default:
+		return db.queryAlbums(`ORDER BY artist_name COLLATE NOCASE, year, name COLLATE NOCASE`)
+	}
+}
+
+// AlbumArtistID returns the ID of the artist an album is filed under, which
+// for compilations differs from its tracks' artists. It is "" for an
+// unknown album.
+func (db *DB) AlbumArtistID(albumID string) string {
+	var id string
+	db.Conn.QueryRow(`SELECT artist_id FROM albums WHERE id = ?`, albumID).Scan(&id)
+	return id
+}
+
+// AlbumCoverArt returns the server's cover art ID for an album, which may
+// differ from the album ID, or "" if it has none.
+func (db *DB) AlbumCoverArt(albumID string) string {
+	var id string
+	db.Conn.QueryRow(`SELECT cover_art FROM albums WHERE id = ?`, albumID).Scan(&id)
+	return id
+}
+
+// queryAlbums selects albums with the given WHERE/ORDER BY clause.
+func (db *DB) queryAlbums(clause string, args ...any) ([]AlbumRow, error) {
 	rows, err := db.Conn.Query(`
-		SELECT id, name, artist_id, year, song_count, duration_ms, cover_art
-		FROM albums WHERE artist_id = ? ORDER BY year, name COLLATE NOCASE
-	`, artistID)
+		SELECT id, name, artist_id, artist_name, year, song_count, duration_ms, cover_art
+		FROM albums `+clause, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -70,7 +164,7 @@ func (db *DB) AlbumsForArtist(artistID string) ([]AlbumRow, error) {
 	var albums []AlbumRow
 	for rows.Next() {
 		var a AlbumRow
-		if err := rows.Scan(&a.ID, &a.Name, &a.ArtistID, &a.Year, &a.SongCount, &a.DurationMs, &a.CoverArt); err != nil {
+		if err := rows.Scan(&a.ID, &a.Name, &a.ArtistID, &a.ArtistName, &a.Year, &a.SongCount, &a.DurationMs, &a.CoverArt); err != nil {
 			return nil, err
 		}
 		albums = append(albums, a)
@@ -80,14 +174,34 @@ func (db *DB) AlbumsForArtist(artistID string) ([]AlbumRow, error) {
 
 // TracksForArtist returns all tracks for an artist, ordered by album year, disc, track.
 func (db *DB) TracksForArtist(artistID string) ([]TrackRow, error) {
-	rows, err := db.Conn.Query(`
-		SELECT t.id, t.title, t.artist, a.name, t.album_id, t.track_num, t.disc_num, t.duration_ms,
-			a.year, t.genre, t.format, t.shuffle_exclude, COALESCE(t.linked_next_id, '')
-		FROM tracks t
-		JOIN albums a ON t.album_id = a.id
+	return db.queryTracks(`
 		WHERE t.artist_id = ?
 		ORDER BY a.year, a.name COLLATE NOCASE, t.disc_num, t.track_num
 	`, artistID)
+}
+
+// trackSelect is the column list read by scanTrack.
+const trackSelect = `
+	SELECT t.id, t.title, t.artist, a.name, t.album_id, t.artist_id, t.track_num, t.disc_num,
+		t.duration_ms, a.year, t.genre, t.format, t.bitrate, t.shuffle_exclude, COALESCE(t.linked_next_id, ''),
+		t.rating, t.play_count, t.cover_art
+	FROM tracks t
+	JOIN albums a ON t.album_id = a.id
+`
+
+// scanTrack reads a row selected by trackSelect.
+func scanTrack(rows *sql.Rows) (TrackRow, error) {
+	var t TrackRow
+	err := rows.Scan(&t.ID, &t.Title, &t.Artist, &t.Album, &t.AlbumID, &t.ArtistID, &t.TrackNum,
+		&t.DiscNum, &t.DurationMs, &t.Year, &t.Genre, &t.Format, &t.BitRate, &t.ShuffleExclude, &t.LinkedNextID,
+		&t.Rating, &t.PlayCount, &t.CoverArt)
+	return t, err
+}
+
+// queryTracks runs trackSelect with the given WHERE/ORDER BY clause and
+// applies the configured in-album ordering.
+func (db *DB) queryTracks(clause string, args ...any) ([]TrackRow, error) {
+	rows, err := db.Conn.Query(trackSelect+clause, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -95,14 +209,17 @@ func (db *DB) TracksForArtist(artistID string) ([]TrackRow, error) {
 
 	var tracks []TrackRow
 	for rows.Next() {
-		var t TrackRow
-		if err := rows.Scan(&t.ID, &t.Title, &t.Artist, &t.Album, &t.AlbumID, &t.TrackNum, &t.DiscNum,
-			&t.DurationMs, &t.Year, &t.Genre, &t.Format, &t.ShuffleExclude, &t.LinkedNextID); err != nil {
+		t, err := scanTrack(rows)
+		if err != nil {
 			return nil, err
 		}
 		tracks = append(tracks, t)
 	}
-	return tracks, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	db.orderAlbumTracks(tracks)
+	return tracks, nil
 }
 
 // SearchResult holds a single search hit with its type.
@@ -118,11 +235,22 @@ type SearchResult struct {
 }
 
 // Search performs a fuzzy search across the library using FTS5.
-// Returns up to `limit` results, grouped by type.
-func (db *DB) Search(query string, limit int) ([]SearchResult, error) {
+// Returns results for up to `limit` matching tracks, grouped by type and
+// restricted to the given kinds ("artist", "album", "track"; none means all).
+func (db *DB) Search(query string, limit int, kinds []string) ([]SearchResult, error) {
+	results, _, err := db.SearchPage(query, limit, 0, kinds)
+	return results, err
+}
+
+// SearchPage is Search skipping the first offset matching tracks, for
+// loading further pages. It also returns how many tracks matched in the
+// page, whatever kinds were kept, so a short page means the end. Artists
+// and albums are only deduplicated within the page.
+func (db *DB) SearchPage(query string, limit, offset int, kinds []string) ([]SearchResult, int, error) {
 	if query == "" {
-		return nil, nil
+		return nil, 0, nil
 	}
+	want := func(kind string) bool { return len(kinds) == 0 || slices.Contains(kinds, kind) }
 
 	// FTS5 prefix search: append * for partial matching.
 	ftsQuery := query + "*"
@@ -135,10 +263,10 @@ func (db *DB) Search(query string, limit int) ([]SearchResult, error) {
 		JOIN albums a ON t.album_id = a.id
 		WHERE tracks_fts MATCH ?
 		ORDER BY fts.rank
-		LIMIT ?
-	`, ftsQuery, limit)
+		LIMIT ? OFFSET ?
+	`, ftsQuery, limit, offset)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
@@ -146,16 +274,18 @@ func (db *DB) Search(query string, limit int) ([]SearchResult, error) {
 	seenArtists := make(map[string]bool)
 	seenAlbums := make(map[string]bool)
 	var results []SearchResult
+	matched := 0
 
 	for rows.Next() {
 		var id, title, artist, album, albumID, artistID string
 		var year int
 		if err := rows.Scan(&id, &title, &artist, &album, &albumID, &artistID, &year); err != nil {
-			return nil, err
+			return nil, 0, err
 		}
+		matched++
 
 		// Emit unique artists.
-		if !seenArtists[artistID] {
+		if want("artist") && !seenArtists[artistID] {
 			seenArtists[artistID] = true
 			results = append(results, SearchResult{
 				Kind:     "artist",
@@ -166,7 +296,7 @@ func (db *DB) Search(query string, limit int) ([]SearchResult, error) {
 		}
 
 		// Emit unique albums.
-		if !seenAlbums[albumID] {
+		if want("album") && !seenAlbums[albumID] {
 			seenAlbums[albumID] = true
 			results = append(results, SearchResult{
 				Kind:     "album",
@@ -180,43 +310,148 @@ func (db *DB) Search(query string, limit int) ([]SearchResult, error) {
 		}
 
 		// Emit track.
-		results = append(results, SearchResult{
-			Kind:     "track",
-			ID:       id,
-			Title:    title,
-			Artist:   artist,
-			Album:    album,
-			AlbumID:  albumID,
-			ArtistID: artistID,
-			Year:     year,
-		})
+		if want("track") {
+			results = append(results, SearchResult{
+				Kind:     "track",
+				ID:       id,
+				Title:    title,
+				Artist:   artist,
+				Album:    album,
+				AlbumID:  albumID,
+				ArtistID: artistID,
+				Year:     year,
+			})
+		}
 	}
 
-	return results, rows.Err()
+	return results, matched, rows.Err()
 }
 
 // TracksForAlbum returns all tracks for an album, sorted by disc and track number.
 func (db *DB) TracksForAlbum(albumID string) ([]TrackRow, error) {
-	rows, err := db.Conn.Query(`
-		SELECT t.id, t.title, t.artist, a.name, t.album_id, t.track_num, t.disc_num, t.duration_ms,
-			a.year, t.genre, t.format, t.shuffle_exclude, COALESCE(t.linked_next_id, '')
-		FROM tracks t
-		JOIN albums a ON t.album_id = a.id
-		WHERE t.album_id = ? ORDER BY t.disc_num, t.track_num
-	`, albumID)
+	return db.queryTracks(`WHERE t.album_id = ? ORDER BY t.disc_num, t.track_num`, albumID)
+}
+
+// tracksByIDBatch is how many IDs TracksByID looks up per query, well
+// under SQLite's limit on bound parameters.
+const tracksByIDBatch = 500
+
+// TracksByID returns the tracks with the given IDs in the order given,
+// repeats included. IDs not in the library are skipped.
+func (db *DB) TracksByID(ids []string) ([]TrackRow, error) {
+	found := make(map[string]TrackRow, len(ids))
+	for batch := range slices.Chunk(ids, tracksByIDBatch) {
+		args := make([]any, len(batch))
+		for i, id := range batch {
+			args[i] = id
+		}
+		rows, err := db.Conn.Query(trackSelect+`WHERE t.id IN (?`+strings.Repeat(", ?", len(batch)-1)+`)`, args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			t, err := scanTrack(rows)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			found[t.ID] = t
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	var tracks []TrackRow
+	for _, id := range ids {
+		if t, ok := found[id]; ok {
+			tracks = append(tracks, t)
+		}
+	}
+	return tracks, nil
+}
+
+// FindDuplicates groups tracks that look like copies of each other: the same
+// title, artist and album name (ignoring case) with durations within
+// tolerance. Each group holds two or more tracks; groups are sorted by
+// artist, album and title.
+func (db *DB) FindDuplicates(tolerance time.Duration) ([][]TrackRow, error) {
+	tracks, err := db.queryTracks("")
 	if err != nil {
 		return nil, err
 	}
+
+	key := func(t TrackRow) string {
+		return strings.ToLower(strings.TrimSpace(t.Artist)) + "\x00" +
+			strings.ToLower(strings.TrimSpace(t.Album)) + "\x00" +
+			strings.ToLower(strings.TrimSpace(t.Title))
+	}
+	slices.SortFunc(tracks, func(a, b TrackRow) int {
+		return cmp.Or(strings.Compare(key(a), key(b)), a.DurationMs-b.DurationMs)
+	})
+
+	tol := int(tolerance.Milliseconds())
+	var groups [][]TrackRow
+	for start := 0; start < len(tracks); {
+		// Extend the run while the key matches and each duration stays
+		// within tolerance of the previous one.
+		end := start + 1
+		for end < len(tracks) && key(tracks[end]) == key(tracks[start]) &&
+			tracks[end].DurationMs-tracks[end-1].DurationMs <= tol {
+			end++
+		}
+		if end-start > 1 {
+			groups = append(groups, tracks[start:end])
+		}
+		start = end
+	}
+	return groups, nil
+}
+
+// orderAlbumTracks re-sorts each album's run of tracks by disc and title when
+// the title order is configured, or when the album's track numbers are all
+// zero (badly tagged rips would otherwise play in insertion order).
+func (db *DB) orderAlbumTracks(tracks []TrackRow) {
+	for start := 0; start < len(tracks); {
+		end := start + 1
+		for end < len(tracks) && tracks[end].AlbumID == tracks[start].AlbumID {
+			end++
+		}
+		album := tracks[start:end]
+		untagged := !slices.ContainsFunc(album, func(t TrackRow) bool { return t.TrackNum != 0 })
+		if db.trackOrder == TrackOrderTitle || untagged {
+			slices.SortStableFunc(album, func(a, b TrackRow) int {
+				if a.DiscNum != b.DiscNum {
+					return a.DiscNum - b.DiscNum
+				}
+				return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+			})
+		}
+		start = end
+	}
+}
+
+// EachTrack calls fn for every track in the library, ordered by artist,
+// album and track number, reading rows as it goes rather than loading the
+// whole library. It stops at the first error fn returns.
+func (db *DB) EachTrack(fn func(TrackRow) error) error {
+	rows, err := db.Conn.Query(trackSelect + `
+		ORDER BY a.artist_name COLLATE NOCASE, a.year, a.name COLLATE NOCASE, t.disc_num, t.track_num
+	`)
+	if err != nil {
+		return err
+	}
 	defer rows.Close()
 
-	var tracks []TrackRow
 	for rows.Next() {
-		var t TrackRow
-		if err := rows.Scan(&t.ID, &t.Title, &t.Artist, &t.Album, &t.AlbumID, &t.TrackNum, &t.DiscNum,
-			&t.DurationMs, &t.Year, &t.Genre, &t.Format, &t.ShuffleExclude, &t.LinkedNextID); err != nil {
-			return nil, err
+		t, err := scanTrack(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(t); err != nil {
+			return err
 		}
-		tracks = append(tracks, t)
 	}
-	return tracks, rows.Err()
+	return rows.Err()
 }
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestTracksByID(t *testing.T) {
+	db := openTestDB(t)
+	// More tracks than one batch, so the lookup spans queries.
+	var ids []string
+	for i := range tracksByIDBatch + 10 {
+		id := fmt.Sprintf("t%d", i)
+		addTrack(t, db, id, id, "Low")
+		ids = append(ids, id)
+	}
+	slices.Reverse(ids)
+	ids = append(ids, "gone", "t3", ids[0])
+
+	tracks, err := db.TracksByID(ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, tr := range tracks {
+		got = append(got, tr.ID)
+	}
+	want := slices.DeleteFunc(slices.Clone(ids), func(id string) bool { return id == "gone" })
+	if len(got) != len(want) {
+		t.Fatalf("got %d tracks, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("track %d is %s, want %s", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SessionSnapshot is the name the queue left at quit is kept under, to be
+// loaded again on the next start. Snapshots the user saves always have a
+// name, so it never clashes, and it isn't listed with them.
+const SessionSnapshot = ""
+
+// ErrNoSnapshot is returned when loading a snapshot that isn't saved.
+var ErrNoSnapshot = errors.New("no snapshot")
+
+// QueueSnapshot is a queue saved under a name so it can be restored later.
+type QueueSnapshot struct {
+	Name       string
+	Current    int           // index of the playing track
+	Elapsed    time.Duration // position within the playing track
+	SavedAt    time.Time
+	TrackCount int
+	// Tracks is filled by LoadQueueSnapshot. Tracks no longer in the library
+	// are left out, with Current adjusted to match; if the playing track is
+	// one of them, Current moves to the next and Elapsed is zeroed.
+	Tracks []TrackRow
+}
+
+// SaveQueueSnapshot stores the queue's track IDs under name, replacing any
+// snapshot already saved with that name.
+func (db *DB) SaveQueueSnapshot(name string, trackIDs []string, current int, elapsed time.Duration) error {
+	tx, err := db.Conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := deleteSnapshot(tx, name); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO queue_snapshots (name, current, elapsed_ms, saved_at) VALUES (?, ?, ?, ?)
+	`, name, current, elapsed.Milliseconds(), time.Now().Unix()); err != nil {
+		return fmt.Errorf("saving snapshot: %w", err)
+	}
+
+	stmt, err := tx.Prepare(`INSERT INTO queue_snapshot_tracks (name, position, track_id) VALUES (?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for i, id := range trackIDs {
+		if _, err := stmt.Exec(name, i, id); err != nil {
+			return fmt.Errorf("saving snapshot track: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ListQueueSnapshots returns the saved snapshots, newest first, without
+// tracks or the session snapshot.
+func (db *DB) ListQueueSnapshots() ([]QueueSnapshot, error) {
+	rows, err := db.Conn.Query(`
+		SELECT s.name, s.current, s.elapsed_ms, s.saved_at,
+			(SELECT COUNT(*) FROM queue_snapshot_tracks t WHERE t.name = s.name)
+		FROM queue_snapshots s
+		WHERE s.name != ?
+		ORDER BY s.saved_at DESC
+	`, SessionSnapshot)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snapshots []QueueSnapshot
+	for rows.Next() {
+		var s QueueSnapshot
+		var elapsedMs, savedAt int64
+		if err := rows.Scan(&s.Name, &s.Current, &elapsedMs, &savedAt, &s.TrackCount); err != nil {
+			return nil, err
+		}
+		s.Elapsed = time.Duration(elapsedMs) * time.Millisecond
+		s.SavedAt = time.Unix(savedAt, 0)
+		snapshots = append(snapshots, s)
+	}
+	return snapshots, rows.Err()
+}
+
+// LoadQueueSnapshot returns the named snapshot with its tracks in queue order.
+func (db *DB) LoadQueueSnapshot(name string) (*QueueSnapshot, error) {
+	s := QueueSnapshot{Name: name}
+	var elapsedMs, savedAt int64
+	err := db.Conn.QueryRow(`
+		SELECT current, elapsed_ms, saved_at FROM queue_snapshots WHERE name = ?
+	`, name).Scan(&s.Current, &elapsedMs, &savedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w named %q", ErrNoSnapshot, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.Elapsed = time.Duration(elapsedMs) * time.Millisecond
+	s.SavedAt = time.Unix(savedAt, 0)
+
+	rows, err := db.Conn.Query(`
+		SELECT track_id FROM queue_snapshot_tracks WHERE name = ? ORDER BY position
+	`, name)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	s.TrackCount = len(ids)
+
+	if s.Tracks, err = db.TracksByID(ids); err != nil {
+		return nil, err
+	}
+	// TracksByID keeps the order and skips what's gone, so walking both lists
+	// together finds where the playing track ended up. If it's gone, play
+	// the next one that's left, from its start.
+	current, j := -1, 0
+	for i, id := range ids {
+		kept := j < len(s.Tracks) && s.Tracks[j].ID == id
+		if i == s.Current {
+			current = j
+			if !kept {
+				s.Elapsed = 0
+			}
+			break
+		}
+		if kept {
+			j++
+		}
+	}
+	s.Current = min(max(current, 0), max(len(s.Tracks)-1, 0))
+	return &s, nil
+}
+
+// DeleteQueueSnapshot removes the named snapshot.
+func (db *DB) DeleteQueueSnapshot(name string) error {
+	tx, err := db.Conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := deleteSnapshot(tx, name); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func deleteSnapshot(tx *sql.Tx, name string) error {
+	if _, err := tx.Exec(`DELETE FROM queue_snapshot_tracks WHERE name = ?`, name); err != nil {
+		return fmt.Errorf("deleting snapshot tracks: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM queue_snapshots WHERE name = ?`, name); err != nil {
+		return fmt.Errorf("deleting snapshot: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLoadSnapshotWithoutPlayingTrack(t *testing.T) {
+	db := openTestDB(t)
+	for _, id := range []string{"t1", "t2", "t3"} {
+		addTrack(t, db, id, id, "Low")
+	}
+	if err := db.SaveQueueSnapshot("q", []string{"t1", "t2", "t3"}, 1, 30*time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	// An earlier track going keeps the playing one and its position.
+	if _, err := db.Conn.Exec(`DELETE FROM tracks WHERE id = 't1'`); err != nil {
+		t.Fatal(err)
+	}
+	s, err := db.LoadQueueSnapshot("q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Tracks[s.Current].ID; got != "t2" || s.Elapsed != 30*time.Second {
+		t.Errorf("restored at %s %v, want t2 30s", got, s.Elapsed)
+	}
+
+	// The playing track going moves on to the next, from its start.
+	if _, err := db.Conn.Exec(`DELETE FROM tracks WHERE id = 't2'`); err != nil {
+		t.Fatal(err)
+	}
+	if s, err = db.LoadQueueSnapshot("q"); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Tracks) != 1 || s.Tracks[s.Current].ID != "t3" || s.Elapsed != 0 {
+		t.Errorf("restored %d tracks at %s %v, want t3 from the start", len(s.Tracks), s.Tracks[s.Current].ID, s.Elapsed)
+	}
+}
+
+func TestSessionSnapshotNotListed(t *testing.T) {
+	db := openTestDB(t)
+	addTrack(t, db, "t1", "t1", "Low")
+	if err := db.SaveQueueSnapshot(SessionSnapshot, []string{"t1"}, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveQueueSnapshot("q", []string{"t1"}, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	snapshots, err := db.ListQueueSnapshots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 1 || snapshots[0].Name != "q" {
+		t.Errorf("listed %v, want just q", snapshots)
+	}
+	if _, err := db.LoadQueueSnapshot("gone"); !errors.Is(err, ErrNoSnapshot) {
+		t.Errorf("loading a missing snapshot: %v, want ErrNoSnapshot", err)
+	}
+}
@@ -0,0 +1,50 @@
+package db
+
+// TrackExists reports whether a track with the given ID is in the library.
+func (db *DB) TrackExists(id string) bool {
+	var n int
+	db.Conn.QueryRow(`SELECT COUNT(*) FROM tracks WHERE id = ?`, id).Scan(&n)
+	return n > 0
+}
+
+// TrackIDsByName returns the IDs of tracks with the given artist and title,
+// ignoring case.
+func (db *DB) TrackIDsByName(artist, title string) ([]string, error) {
+	rows, err := db.Conn.Query(`
+		SELECT id FROM tracks
+		WHERE artist = ? COLLATE NOCASE AND title = ? COLLATE NOCASE
+	`, artist, title)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// MergeTrackStats folds imported stats into a track: a rating above 0
+// replaces the stored one, and the play count only ever grows, so importing
+// the same data twice changes nothing.
+func (db *DB) MergeTrackStats(id string, rating, playCount int) error {
+	_, err := db.Conn.Exec(`
+		UPDATE tracks SET
+			rating = CASE WHEN ? > 0 THEN ? ELSE rating END,
+			play_count = MAX(play_count, ?)
+		WHERE id = ?
+	`, rating, rating, playCount, id)
+	return err
+}
+
+// CountPlay adds one to a track's play count, once it has played through.
+func (db *DB) CountPlay(id string) error {
+	_, err := db.Conn.Exec(`UPDATE tracks SET play_count = play_count + 1 WHERE id = ?`, id)
+	return err
+}
@@ -0,0 +1,179 @@
+// Package importer merges listening stats from other sources into the
+// local library.
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/simonhull/kitsune/internal/db"
+)
+
+// Format names an input format.
+type Format string
+
+const (
+	// FormatKitsune is the JSON written by "kitsune export-library".
+	FormatKitsune Format = "kitsune"
+	// FormatListenBrainz is a ListenBrainz listens export: one JSON listen
+	// per line. Each listen counts as one play.
+	FormatListenBrainz Format = "listenbrainz"
+	// FormatLastFM is a Last.fm scrobble CSV: artist, album, title, date.
+	// Each row counts as one play.
+	FormatLastFM Format = "lastfm"
+)
+
+// Record is the stats for one track. ID is set when the source knows the
+// library's track IDs; otherwise tracks are matched by artist and title.
+type Record struct {
+	ID        string
+	Artist    string
+	Title     string
+	Rating    int // 0 leaves the stored rating alone
+	PlayCount int
+}
+
+// Result counts how an import went.
+type Result struct {
+	Matched int
+	Skipped int // no match, or more than one track by that artist and title
+}
+
+// Parse reads records in the given format. Plays for the same artist and
+// title are added up.
+func Parse(r io.Reader, format Format) ([]Record, error) {
+	switch format {
+	case FormatKitsune:
+		return parseKitsune(r)
+	case FormatListenBrainz:
+		return parseListenBrainz(r)
+	case FormatLastFM:
+		return parseLastFM(r)
+	}
+	return nil, fmt.Errorf("unknown format %q (want kitsune, listenbrainz or lastfm)", format)
+}
+
+func parseKitsune(r io.Reader) ([]Record, error) {
+	var tracks []struct {
+		ID        string `json:"id"`
+		Artist    string `json:"artist"`
+		Title     string `json:"title"`
+		Rating    int    `json:"rating"`
+		PlayCount int    `json:"play_count"`
+	}
+	if err := json.NewDecoder(r).Decode(&tracks); err != nil {
+		return nil, fmt.Errorf("reading export: %w", err)
+	}
+	recs := make([]Record, 0, len(tracks))
+	for _, t := range tracks {
+		if t.Rating < 0 || t.Rating > 5 {
+			t.Rating = 0
+		}
+		recs = append(recs, Record{ID: t.ID, Artist: t.Artist, Title: t.Title, Rating: t.Rating, PlayCount: t.PlayCount})
+	}
+	return recs, nil
+}
+
+func parseListenBrainz(r io.Reader) ([]Record, error) {
+	var plays playCounter
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		if strings.TrimSpace(sc.Text()) == "" {
+			continue
+		}
+		var listen struct {
+			TrackMetadata struct {
+				ArtistName string `json:"artist_name"`
+				TrackName  string `json:"track_name"`
+			} `json:"track_metadata"`
+		}
+		if err := json.Unmarshal(sc.Bytes(), &listen); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		plays.add(listen.TrackMetadata.ArtistName, listen.TrackMetadata.TrackName)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return plays.recs, nil
+}
+
+func parseLastFM(r io.Reader) ([]Record, error) {
+	var plays playCounter
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	for {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(row) < 3 || strings.EqualFold(row[0], "artist") {
+			continue // short row or header
+		}
+		plays.add(row[0], row[2])
+	}
+	return plays.recs, nil
+}
+
+// playCounter adds up plays per artist and title, keeping first-seen order.
+type playCounter struct {
+	index map[string]int
+	recs  []Record
+}
+
+func (p *playCounter) add(artist, title string) {
+	artist, title = strings.TrimSpace(artist), strings.TrimSpace(title)
+	if artist == "" || title == "" {
+		return
+	}
+	if p.index == nil {
+		p.index = make(map[string]int)
+	}
+	key := strings.ToLower(artist) + "\x00" + strings.ToLower(title)
+	i, ok := p.index[key]
+	if !ok {
+		i = len(p.recs)
+		p.index[key] = i
+		p.recs = append(p.recs, Record{Artist: artist, Title: title})
+	}
+	p.recs[i].PlayCount++
+}
+
+// Apply merges records into the library. A record matches by ID when the
+// library has that track, else by artist and title when exactly one track
+// has them; anything else is skipped rather than guessed at.
+func Apply(database *db.DB, recs []Record) (Result, error) {
+	var res Result
+	for _, rec := range recs {
+		id := ""
+		if rec.ID != "" && database.TrackExists(rec.ID) {
+			id = rec.ID
+		} else if rec.Artist != "" && rec.Title != "" {
+			ids, err := database.TrackIDsByName(rec.Artist, rec.Title)
+			if err != nil {
+				return res, err
+			}
+			if len(ids) == 1 {
+				id = ids[0]
+			}
+		}
+		if id == "" {
+			res.Skipped++
+			continue
+		}
+		if err := database.MergeTrackStats(id, rec.Rating, rec.PlayCount); err != nil {
+			return res, err
+		}
+		res.Matched++
+	}
+	return res, nil
+}
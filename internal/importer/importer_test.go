@@ -0,0 +1,143 @@
+package importer
+
+import (
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/simonhull/kitsune/internal/db"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		input  string
+		want   []Record
+	}{
+		{
+			name:   "kitsune",
+			format: FormatKitsune,
+			input: `[
+				{"id": "t1", "artist": "Björk", "title": "Jóga", "rating": 5, "play_count": 12},
+				{"id": "t2", "artist": "Low", "title": "Words", "rating": 9, "play_count": 3}
+			]`,
+			want: []Record{
+				{ID: "t1", Artist: "Björk", Title: "Jóga", Rating: 5, PlayCount: 12},
+				{ID: "t2", Artist: "Low", Title: "Words", PlayCount: 3}, // out of range rating dropped
+			},
+		},
+		{
+			name:   "listenbrainz",
+			format: FormatListenBrainz,
+			input: `{"listened_at": 1, "track_metadata": {"artist_name": "Low", "track_name": "Words"}}
+{"listened_at": 2, "track_metadata": {"artist_name": "Björk", "track_name": "Jóga"}}
+
+{"listened_at": 3, "track_metadata": {"artist_name": "low", "track_name": "WORDS "}}
+{"listened_at": 4, "track_metadata": {"artist_name": "", "track_name": "Untitled"}}
+`,
+			want: []Record{
+				{Artist: "Low", Title: "Words", PlayCount: 2},
+				{Artist: "Björk", Title: "Jóga", PlayCount: 1},
+			},
+		},
+		{
+			name:   "lastfm",
+			format: FormatLastFM,
+			input: `Artist,Album,Title,Date
+Low,Things We Lost in the Fire,Words,01 Jan 2024 10:00
+Björk,Homogenic,Jóga,02 Jan 2024 11:00
+Low,Things We Lost in the Fire,Words,03 Jan 2024 12:00
+short row
+`,
+			want: []Record{
+				{Artist: "Low", Title: "Words", PlayCount: 2},
+				{Artist: "Björk", Title: "Jóga", PlayCount: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(strings.NewReader(tt.input), tt.format)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		input  string
+	}{
+		{"unknown format", "itunes", ""},
+		{"kitsune not an array", FormatKitsune, `{"id": "t1"}`},
+		{"listenbrainz bad line", FormatListenBrainz, "{\"track_metadata\": {}}\nnot json\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(tt.input), tt.format); err == nil {
+				t.Error("Parse succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	database, err := db.Open(slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer database.Close()
+
+	// "Creep" is on two albums, so a record matching it by name is ambiguous.
+	for _, tr := range []struct{ id, artist, title, album string }{
+		{"t1", "Björk", "Jóga", "al1"},
+		{"t2", "Radiohead", "Creep", "al2"},
+		{"t3", "Radiohead", "Creep", "al3"},
+		{"t4", "Low", "Words", "al4"},
+	} {
+		if _, err := database.Conn.Exec(`INSERT INTO tracks (id, title, artist, album_id) VALUES (?, ?, ?, ?)`,
+			tr.id, tr.title, tr.artist, tr.album); err != nil {
+			t.Fatalf("inserting track: %v", err)
+		}
+	}
+
+	res, err := Apply(database, []Record{
+		{ID: "t1", Rating: 4, PlayCount: 7},                       // by ID
+		{ID: "gone", Artist: "low", Title: "words", PlayCount: 2}, // unknown ID, by name
+		{Artist: "Radiohead", Title: "Creep", PlayCount: 9},       // ambiguous
+		{Artist: "Nobody", Title: "Nothing", PlayCount: 1},        // no match
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if res != (Result{Matched: 2, Skipped: 2}) {
+		t.Errorf("Apply = %+v, want 2 matched and 2 skipped", res)
+	}
+
+	for _, want := range []struct {
+		id            string
+		rating, plays int
+	}{
+		{"t1", 4, 7},
+		{"t2", 0, 0},
+		{"t3", 0, 0},
+		{"t4", 0, 2},
+	} {
+		var rating, plays int
+		if err := database.Conn.QueryRow(`SELECT rating, play_count FROM tracks WHERE id = ?`, want.id).Scan(&rating, &plays); err != nil {
+			t.Fatalf("reading %s: %v", want.id, err)
+		}
+		if rating != want.rating || plays != want.plays {
+			t.Errorf("%s: rating %d, %d plays; want %d, %d", want.id, rating, plays, want.rating, want.plays)
+		}
+	}
+}
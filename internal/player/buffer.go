@@ -0,0 +1,231 @@
+package player
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	// maxBufferAhead caps how far the background reader may run ahead of playback.
+	maxBufferAhead = 8 << 20
+
+	// compactThreshold is how many consumed bytes accumulate before the buffer is compacted.
+	compactThreshold = 256 << 10
+
+	// prebufferTimeout bounds how long Play waits for the initial buffer on a stalled link.
+	prebufferTimeout = 10 * time.Second
+
+	// fallbackBytesPerMs assumes a 320 kbps stream when the size is unknown.
+	fallbackBytesPerMs = 40.0
+)
+
+// errNotKept is returned by Seek on a buffer that isn't keeping its stream.
+var errNotKept = errors.New("stream isn't kept in memory")
+
+// streamBuffer reads an HTTP body into memory in the background so playback
+// starts from a filled buffer and rides out brief network stalls. A buffer
+// that keeps its stream holds on to every byte, up to a limit, so it can
+// seek back over what it has read.
+type streamBuffer struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	src    io.ReadCloser
+	buf    []byte
+	off    int   // read offset into buf
+	keep   int   // most bytes to keep for seeking; 0 once exceeded, or if not keeping
+	err    error // terminal error from src (io.EOF on a clean end)
+	closed bool
+}
+
+// newStreamBuffer starts buffering src, keeping up to keep bytes of it.
+func newStreamBuffer(src io.ReadCloser, keep int) *streamBuffer {
+	b := &streamBuffer{src: src, keep: keep}
+	b.cond = sync.NewCond(&b.mu)
+	go b.fill()
+	return b
+}
+
+// fill copies from the source until it ends or the buffer is closed.
+func (b *streamBuffer) fill() {
+	chunk := make([]byte, 32<<10)
+	for {
+		b.mu.Lock()
+		for b.keep == 0 && len(b.buf)-b.off >= maxBufferAhead && !b.closed {
+			b.cond.Wait()
+		}
+		closed := b.closed
+		b.mu.Unlock()
+		if closed {
+			return
+		}
+
+		n, err := b.src.Read(chunk)
+
+		b.mu.Lock()
+		b.buf = append(b.buf, chunk[:n]...)
+		if b.keep > 0 && len(b.buf) > b.keep {
+			// Too big to keep: stream it instead, letting go of what's been
+			// read rather than holding on to a buffer the size of the limit.
+			b.keep = 0
+			b.buf = append([]byte(nil), b.buf[b.off:]...)
+			b.off = 0
+		}
+		if err != nil {
+			b.err = err
+		}
+		b.cond.Broadcast()
+		b.mu.Unlock()
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// WaitFor blocks until n bytes are buffered, the source ends, or timeout elapses.
+func (b *streamBuffer) WaitFor(n int, timeout time.Duration) {
+	expired := false
+	timer := time.AfterFunc(timeout, func() {
+		b.mu.Lock()
+		expired = true
+		b.cond.Broadcast()
+		b.mu.Unlock()
+	})
+	defer timer.Stop()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for len(b.buf)-b.off < n && b.err == nil && !b.closed && !expired {
+		b.cond.Wait()
+	}
+}
+
+// Read returns buffered bytes, blocking until data arrives or the source ends.
+func (b *streamBuffer) Read(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for b.off >= len(b.buf) && b.err == nil && !b.closed {
+		b.cond.Wait()
+	}
+	if b.closed {
+		return 0, io.ErrClosedPipe
+	}
+	if b.off >= len(b.buf) {
+		return 0, b.err
+	}
+
+	n := copy(p, b.buf[b.off:])
+	b.off += n
+	if b.keep == 0 && b.off >= compactThreshold {
+		b.buf = append(b.buf[:0], b.buf[b.off:]...)
+		b.off = 0
+	}
+	b.cond.Broadcast()
+	return n, nil
+}
+
+// Seek moves the read offset of a buffer that keeps its stream. Seeking
+// from the end waits for the whole stream.
+func (b *streamBuffer) Seek(offset int64, whence int) (int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.keep == 0 {
+		return 0, errNotKept
+	}
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += int64(b.off)
+	case io.SeekEnd:
+		for b.keep > 0 && b.err == nil && !b.closed {
+			b.cond.Wait()
+		}
+		if b.keep == 0 {
+			return 0, errNotKept
+		}
+		offset += int64(len(b.buf))
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+	b.off = int(offset)
+	b.cond.Broadcast()
+	return offset, nil
+}
+
+// Kept reports whether the buffer is still keeping its stream.
+func (b *streamBuffer) Kept() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.keep > 0
+}
+
+// Complete reports whether the whole stream is kept in memory, so seeking
+// anywhere in it never waits on the network.
+func (b *streamBuffer) Complete() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.complete()
+}
+
+func (b *streamBuffer) complete() bool {
+	return b.keep > 0 && b.err == io.EOF && !b.closed
+}
+
+// Bytes returns the whole stream once it's kept in memory, or nil before.
+// The buffer never changes once complete, so the bytes can be read freely.
+func (b *streamBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.complete() {
+		return nil
+	}
+	return b.buf
+}
+
+// WaitComplete blocks until the whole stream is kept in memory, reporting
+// false if it failed, got too big to keep, or the buffer was closed first.
+func (b *streamBuffer) WaitComplete() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for b.keep > 0 && b.err == nil && !b.closed {
+		b.cond.Wait()
+	}
+	return b.complete()
+}
+
+// Err returns the error the download failed with; nil while it's still
+// going or once it has ended cleanly.
+func (b *streamBuffer) Err() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.err == io.EOF {
+		return nil
+	}
+	return b.err
+}
+
+// Close stops the background reader and closes the source.
+func (b *streamBuffer) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	b.cond.Broadcast()
+	b.mu.Unlock()
+	return b.src.Close()
+}
+
+// prebufferBytes estimates how many bytes cover d of audio, from the stream's
+// size and duration when known.
+func prebufferBytes(contentLength int64, durationMs int, d time.Duration) int {
+	bytesPerMs := fallbackBytesPerMs
+	if contentLength > 0 && durationMs > 0 {
+		bytesPerMs = float64(contentLength) / float64(durationMs)
+	}
+	return int(bytesPerMs * float64(d.Milliseconds()))
+}
@@ -0,0 +1,88 @@
+package player
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// bandCutoffs split the spectrum for the level meter: each band is the
+// difference between neighbouring one-pole low-passes, with the last band
+// everything above the top cutoff. Crude, but a few multiplies per sample
+// instead of an FFT.
+var bandCutoffs = [...]float64{60, 150, 400, 1000, 2500, 6000, 12000}
+
+// Bands is how many levels Levels reports.
+const Bands = len(bandCutoffs) + 1
+
+// levelWindow is how much audio each published set of levels covers.
+const levelWindow = 50 * time.Millisecond
+
+// levelMeter measures the RMS level of a few frequency bands of the audio
+// passing through the position tracker. It runs on the speaker goroutine and
+// keeps its own lock, so reading levels never waits on the speaker.
+type levelMeter struct {
+	coef   []float64 // low-pass coefficients, one per cutoff
+	lp     []float64 // low-pass filter states
+	energy []float64 // summed squares per band in the current window
+	n      int       // samples in the current window
+	window int
+
+	mu     sync.Mutex
+	levels []float64 // RMS per band of the last full window
+}
+
+func newLevelMeter() *levelMeter {
+	m := &levelMeter{
+		coef:   make([]float64, len(bandCutoffs)),
+		lp:     make([]float64, len(bandCutoffs)),
+		energy: make([]float64, Bands),
+		window: sampleRate.N(levelWindow),
+	}
+	for i, fc := range bandCutoffs {
+		m.coef[i] = 1 - math.Exp(-2*math.Pi*fc/float64(sampleRate))
+	}
+	return m
+}
+
+// add feeds stereo samples through the filter bank, mixed down to mono.
+func (m *levelMeter) add(samples [][2]float64) {
+	for _, s := range samples {
+		x := (s[0] + s[1]) / 2
+		prev := 0.0
+		for i, a := range m.coef {
+			m.lp[i] += a * (x - m.lp[i])
+			band := m.lp[i] - prev
+			m.energy[i] += band * band
+			prev = m.lp[i]
+		}
+		top := x - prev
+		m.energy[len(m.coef)] += top * top
+
+		m.n++
+		if m.n == m.window {
+			m.publish()
+		}
+	}
+}
+
+// publish turns the window's energy into levels and starts a new window.
+func (m *levelMeter) publish() {
+	levels := make([]float64, len(m.energy))
+	for i, e := range m.energy {
+		levels[i] = math.Sqrt(e / float64(m.n))
+		m.energy[i] = 0
+	}
+	m.n = 0
+
+	m.mu.Lock()
+	m.levels = levels
+	m.mu.Unlock()
+}
+
+// read returns the last published levels.
+func (m *levelMeter) read() []float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.levels
+}
@@ -1,15 +1,21 @@
 package player
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"math"
 	"net/http"
+	"net/url"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/gopxl/beep/v2"
+	"github.com/gopxl/beep/v2/effects"
 	"github.com/gopxl/beep/v2/flac"
 	"github.com/gopxl/beep/v2/mp3"
 	"github.com/gopxl/beep/v2/speaker"
@@ -17,6 +23,60 @@ import (
 
 const sampleRate = beep.SampleRate(44100)
 
+// ErrSuperseded is returned by Play when a later Play or Stop call replaced
+// it before it started.
+var ErrSuperseded = errors.New("playback superseded")
+
+// ErrDecode is wrapped by Play's error when the stream opened but couldn't
+// be decoded, e.g. a corrupt file or a format the decoders don't handle, and
+// by Err's when decoding failed part way through.
+var ErrDecode = errors.New("can't decode stream")
+
+// ErrNotSeekable is returned by Seek when the current stream can't seek.
+var ErrNotSeekable = errors.New("stream is not seekable")
+
+// ErrBuffering is returned by Seek while the current stream is still
+// downloading; seeking needs all of it.
+var ErrBuffering = errors.New("stream is still buffering")
+
+// Config is the user-facing [player] section in config.toml.
+type Config struct {
+	// PrebufferMs is how much audio to buffer before playback starts (0 disables).
+	PrebufferMs int `toml:"prebuffer_ms"`
+	// DecodeBufferKB sizes the read buffer in front of the decoder, per
+	// format (e.g. flac = 256). Formats not listed use defaultDecodeBufferKB.
+	DecodeBufferKB map[string]int `toml:"decode_buffer_kb"`
+	// ResumeRewindSec rewinds this far when resuming from pause, to help
+	// pick the thread back up (0 disables).
+	ResumeRewindSec int `toml:"resume_rewind_sec"`
+	// SeekBufferMB caps how much of a stream is kept in memory so it can
+	// seek, which it can once it has all downloaded. Bigger streams play
+	// but can't seek (0 disables seeking). The default fits a long FLAC
+	// track.
+	SeekBufferMB int `toml:"seek_buffer_mb"`
+}
+
+// defaultDecodeBufferKB is the decoder read buffer for unlisted formats.
+const defaultDecodeBufferKB = 64
+
+// DefaultConfig returns the built-in player settings.
+func DefaultConfig() Config {
+	return Config{
+		PrebufferMs: 500,
+		// FLAC decodes in large frames; a bigger buffer means fewer small reads.
+		DecodeBufferKB: map[string]int{"flac": 256, "mp3": 64},
+		SeekBufferMB:   64,
+	}
+}
+
+// decodeBufferSize returns the decoder read buffer size for a format in bytes.
+func (c Config) decodeBufferSize(format string) int {
+	if kb, ok := c.DecodeBufferKB[strings.ToLower(format)]; ok && kb > 0 {
+		return kb << 10
+	}
+	return defaultDecodeBufferKB << 10
+}
+
 // NowPlaying holds info about the currently playing track.
 type NowPlaying struct {
 	TrackID    string
@@ -27,23 +87,39 @@ type NowPlaying struct {
 	Year       int
 	DurationMs int
 	Format     string
+	// StreamFormat is the format requested from the server when transcoding,
+	// or empty when streaming the original file.
+	StreamFormat string
 }
 
 // Player streams and plays audio from a Subsonic server.
 type Player struct {
 	mu       sync.Mutex
 	logger   *slog.Logger
+	http     *http.Client
+	cfg      Config
 	current  *NowPlaying
 	ctrl     *beep.Ctrl
-	streamer beep.StreamSeekCloser
-	body     io.ReadCloser // HTTP response body
+	streamer *seekStreamer
+	volume   *effects.Volume
+	body     io.ReadCloser // buffered HTTP response body
 	tracker  *positionTracker
 	playing  bool
-	done     chan struct{} // signals track ended
+	endErr   error              // why the current track stopped short, if it did
+	metering bool               // measure band levels for Levels
+	gen      uint64             // incremented on every Play and Stop
+	done     chan uint64        // signals track ended, carrying its generation
+	cancel   context.CancelFunc // aborts the in-flight Play, if any
+	level    int                // volume percent, 0–100, kept across tracks
+	muted    bool
 }
 
 // New creates a Player and initializes the audio speaker.
-func New(logger *slog.Logger) (*Player, error) {
+// Streams are fetched with httpClient, or http.DefaultClient if nil.
+func New(cfg Config, httpClient *http.Client, logger *slog.Logger) (*Player, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
 	if logger == nil {
 		logger = slog.Default()
 	}
@@ -55,31 +131,82 @@ func New(logger *slog.Logger) (*Player, error) {
 
 	return &Player{
 		logger: logger.With("component", "player"),
-		done:   make(chan struct{}, 1),
+		http:   httpClient,
+		cfg:    cfg,
+		done:   make(chan uint64, 1),
+		level:  100,
 	}, nil
 }
 
-// Play streams and plays a track from the given URL.
-func (p *Player) Play(streamURL string, format string, info NowPlaying) error {
-	p.Stop()
+// Play streams and plays a track from the given URL. It is safe to call
+// concurrently: each call cancels any Play still opening its stream, and only
+// the most recent one starts audio. Replaced calls return ErrSuperseded.
+// It returns the generation of the track it started, which its end signal
+// on Done carries.
+func (p *Player) Play(streamURL string, format string, info NowPlaying) (uint64, error) {
+	return p.PlayAt(streamURL, format, info, 0)
+}
+
+// PlayAt is Play starting at position at. Like any seek, starting part way
+// in waits for the whole stream; one that can't seek starts from the top.
+func (p *Player) PlayAt(streamURL string, format string, info NowPlaying, at time.Duration) (uint64, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.mu.Lock()
+	p.stopLocked()
+	p.cancel = cancel
+	p.mu.Unlock()
 
 	p.logger.Info("playing", "title", info.Title, "artist", info.Artist, "format", format)
 
 	// Open HTTP stream.
-	resp, err := http.Get(streamURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, streamURL, nil)
 	if err != nil {
-		return fmt.Errorf("streaming %s: %w", info.Title, err)
+		return 0, fmt.Errorf("streaming %s: %w", info.Title, err)
+	}
+	resp, err := p.http.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, ErrSuperseded
+		}
+		// The stream URL carries the server credentials in its query.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = req.URL.Scheme + "://" + req.URL.Host + req.URL.Path
+		}
+		return 0, fmt.Errorf("streaming %s: %w", info.Title, err)
 	}
 	if resp.StatusCode != http.StatusOK {
 		resp.Body.Close()
-		return fmt.Errorf("stream returned %d", resp.StatusCode)
+		return 0, fmt.Errorf("stream returned %d", resp.StatusCode)
 	}
 
-	// Decode based on format.
-	streamer, streamFormat, err := decode(resp.Body, format)
+	// Buffer ahead so slow links don't stutter on the first second, keeping
+	// the stream so it can seek.
+	body := newStreamBuffer(resp.Body, p.cfg.SeekBufferMB<<20)
+	if p.cfg.PrebufferMs > 0 {
+		prebuffer := time.Duration(p.cfg.PrebufferMs) * time.Millisecond
+		body.WaitFor(prebufferBytes(resp.ContentLength, info.DurationMs, prebuffer), prebufferTimeout)
+	}
+
+	// Decode based on format, reading through a buffer sized for it.
+	buffered := bufferedReadCloser{bufio.NewReaderSize(body, p.cfg.decodeBufferSize(format)), body}
+	decoder, streamFormat, err := decode(buffered, format)
 	if err != nil {
-		resp.Body.Close()
-		return fmt.Errorf("decoding %s (%s): %w", info.Title, format, err)
+		body.Close()
+		if ctx.Err() != nil {
+			return 0, ErrSuperseded
+		}
+		return 0, fmt.Errorf("decoding %s (%s): %w: %w", info.Title, format, ErrDecode, err)
+	}
+	streamer := newSeekStreamer(decoder, body, format, streamFormat.SampleRate)
+
+	var start time.Duration
+	if at > 0 {
+		<-streamer.prepared
+		start, err = streamer.SeekTo(at)
+		if err != nil && ctx.Err() == nil {
+			p.logger.Info("can't start part way in", "title", info.Title, "at", at, "err", err)
+		}
 	}
 
 	// Resample to speaker rate if needed.
@@ -91,53 +218,105 @@ func (p *Player) Play(streamURL string, format string, info NowPlaying) error {
 	}
 
 	// Wrap in position tracker.
-	tracker := &positionTracker{Streamer: source}
+	tracker := &positionTracker{Streamer: source, pos: sampleRate.N(start)}
+	p.mu.Lock()
+	if p.metering {
+		tracker.meter = newLevelMeter()
+	}
+	p.mu.Unlock()
 
-	// Wrap in ctrl for pause/resume.
+	// Wrap in ctrl for pause/resume, then volume.
 	ctrl := &beep.Ctrl{Streamer: tracker, Paused: false}
+	volume := &effects.Volume{Streamer: ctrl, Base: 2}
 
+	// Install and start under the lock so a concurrent Play or Stop either
+	// cancels us before this point or sees the new track and stops it.
 	p.mu.Lock()
+	defer p.mu.Unlock()
+	if ctx.Err() != nil {
+		streamer.Close()
+		body.Close()
+		return 0, ErrSuperseded
+	}
+
 	p.current = &info
 	p.ctrl = ctrl
 	p.streamer = streamer
-	p.body = resp.Body
+	p.volume = volume
+	p.applyVolume()
+	p.body = body
 	p.tracker = tracker
 	p.playing = true
+	p.gen++
+	gen := p.gen
 
 	// Drain the done channel in case of a leftover signal.
 	select {
 	case <-p.done:
 	default:
 	}
-	p.mu.Unlock()
 
-	// Play with a callback when the track ends.
-	speaker.Play(beep.Seq(ctrl, beep.Callback(func() {
-		p.mu.Lock()
-		p.playing = false
-		p.mu.Unlock()
-
-		select {
-		case p.done <- struct{}{}:
-		default:
-		}
+	// Play with a callback when the track ends. The callback runs on the
+	// speaker goroutine with the speaker lock held, so it must not take
+	// p.mu (Stop holds p.mu while clearing the speaker).
+	speaker.Play(beep.Seq(volume, beep.Callback(func() {
+		go p.ended(gen)
 	})))
 
-	return nil
+	return gen, nil
 }
 
-// Stop stops the current track.
+// ended records that the track from generation gen finished playing. Tracks
+// that were stopped or replaced in the meantime don't signal at all.
+func (p *Player) ended(gen uint64) {
+	p.mu.Lock()
+	current := p.gen == gen
+	if current {
+		p.playing = false
+		p.endErr = p.streamer.endErr()
+	}
+	p.mu.Unlock()
+	if !current {
+		return
+	}
+
+	select {
+	case p.done <- gen:
+	default:
+	}
+}
+
+// Stop stops the current track and cancels any Play still starting up.
 func (p *Player) Stop() {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.stopLocked()
+}
 
+// stopLocked cancels the in-flight Play and releases the current track.
+// Must be called with mu held.
+func (p *Player) stopLocked() {
+	// Detach from the speaker first: cancelling the request makes the body
+	// fail mid-read, which would otherwise end the stream and fire its callback.
 	if p.ctrl != nil {
 		speaker.Clear()
 	}
+	if p.cancel != nil {
+		p.cancel()
+		p.cancel = nil
+	}
 	p.cleanup()
+
+	// Anything still pending from the stopped track is now stale.
+	p.gen++
+	select {
+	case <-p.done:
+	default:
+	}
 }
 
-// TogglePause pauses if playing, resumes if paused.
+// TogglePause pauses if playing, resumes if paused. Resuming rewinds by
+// ResumeRewindSec, never past the start of the track.
 func (p *Player) TogglePause() {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -147,9 +326,82 @@ func (p *Player) TogglePause() {
 	}
 
 	speaker.Lock()
+	defer speaker.Unlock()
 	p.ctrl.Paused = !p.ctrl.Paused
 	p.playing = !p.ctrl.Paused
-	speaker.Unlock()
+	if p.playing && p.cfg.ResumeRewindSec > 0 {
+		// Unseekable streams just resume where they were.
+		p.seekLocked(-time.Duration(p.cfg.ResumeRewindSec) * time.Second)
+	}
+}
+
+// Seek moves the playback position by delta, clamped to the start and end of
+// the track, and returns the new position. It returns ErrBuffering until the
+// whole stream has downloaded.
+func (p *Player) Seek(delta time.Duration) (time.Duration, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	speaker.Lock()
+	defer speaker.Unlock()
+	return p.seekLocked(delta)
+}
+
+// seekLocked is Seek with p.mu and the speaker lock held.
+func (p *Player) seekLocked(delta time.Duration) (time.Duration, error) {
+	if p.streamer == nil || p.tracker == nil {
+		return 0, nil
+	}
+
+	elapsed := sampleRate.D(p.tracker.pos)
+	target, err := p.streamer.SeekTo(elapsed + delta)
+	if err != nil {
+		return elapsed, err
+	}
+	p.tracker.pos = sampleRate.N(target)
+	return target, nil
+}
+
+// SetVolume sets the volume in percent (clamped to 0–100) and returns it.
+// The level carries over to later tracks.
+func (p *Player) SetVolume(pct int) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.level = min(max(pct, 0), 100)
+	p.applyVolume()
+	return p.level
+}
+
+// Volume returns the volume in percent.
+func (p *Player) Volume() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.level
+}
+
+// ToggleMute mutes or unmutes without changing the volume level, returning
+// whether audio is now muted.
+func (p *Player) ToggleMute() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.muted = !p.muted
+	p.applyVolume()
+	return p.muted
+}
+
+// applyVolume pushes level and muted to the volume effect. Must be called
+// with mu held.
+func (p *Player) applyVolume() {
+	if p.volume == nil {
+		return
+	}
+	speaker.Lock()
+	defer speaker.Unlock()
+	// effects.Volume is logarithmic: gain = Base^Volume.
+	p.volume.Silent = p.muted || p.level == 0
+	if p.level > 0 {
+		p.volume.Volume = math.Log2(float64(p.level) / 100)
+	}
 }
 
 // IsPlaying reports whether audio is currently playing (not paused).
@@ -182,12 +434,61 @@ func (p *Player) Elapsed() float64 {
 	return float64(pos) / float64(sampleRate)
 }
 
-// Done returns a channel that signals when the current track ends.
-func (p *Player) Done() <-chan struct{} {
+// Err returns what ended the current track early, if anything did: an
+// error wrapping ErrDecode when the decoder gave up on bad data part way
+// through, or the download's error. It's nil while the track plays and
+// once it has played through.
+func (p *Player) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.endErr
+}
+
+// EnableLevels turns on band level metering from the next track on. It
+// costs a little work per sample, so it's off unless something shows it.
+func (p *Player) EnableLevels() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.metering = true
+}
+
+// Levels returns the RMS level of each of Bands frequency bands, low to
+// high, over the last few milliseconds played; nil while metering is off
+// or nothing has played yet.
+func (p *Player) Levels() []float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.tracker == nil || p.tracker.meter == nil {
+		return nil
+	}
+	return p.tracker.meter.read()
+}
+
+// Seekable reports whether the current track's stream can seek, or will
+// once it has downloaded.
+func (p *Player) Seekable() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.streamer != nil && p.streamer.Seekable()
+}
+
+// Generation returns the generation of the current track. Stop advances it
+// too, so end signals from a stopped track never match.
+func (p *Player) Generation() uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.gen
+}
+
+// Done returns a channel that signals when a track ends. Each signal carries
+// the generation of the Play call it belongs to, so callers can ignore
+// signals from tracks that have since been replaced.
+func (p *Player) Done() <-chan uint64 {
 	return p.done
 }
 
-// cleanup releases resources. Must be called with mu held.
+// cleanup releases resources, closing the HTTP body so the server frees the
+// stream right away. Must be called with mu held.
 func (p *Player) cleanup() {
 	if p.streamer != nil {
 		p.streamer.Close()
@@ -198,13 +499,21 @@ func (p *Player) cleanup() {
 		p.body = nil
 	}
 	p.ctrl = nil
+	p.volume = nil
 	p.current = nil
 	p.tracker = nil
 	p.playing = false
+	p.endErr = nil
 }
 
 // --- Decoding ---
 
+// bufferedReadCloser reads through a bufio.Reader and closes the underlying body.
+type bufferedReadCloser struct {
+	*bufio.Reader
+	io.Closer
+}
+
 func decode(r io.ReadCloser, format string) (beep.StreamSeekCloser, beep.Format, error) {
 	switch strings.ToLower(format) {
 	case "mp3":
@@ -221,11 +530,15 @@ func decode(r io.ReadCloser, format string) (beep.StreamSeekCloser, beep.Format,
 
 type positionTracker struct {
 	beep.Streamer
-	pos int
+	pos   int
+	meter *levelMeter // nil unless levels are enabled
 }
 
 func (p *positionTracker) Stream(samples [][2]float64) (int, bool) {
 	n, ok := p.Streamer.Stream(samples)
 	p.pos += n
+	if p.meter != nil {
+		p.meter.add(samples[:n])
+	}
 	return n, ok
 }
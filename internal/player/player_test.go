@@ -0,0 +1,466 @@
+package player
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+// mp3FrameSize is the size of a 128 kbps, 44.1 kHz MPEG-1 Layer III frame.
+const mp3FrameSize = 417
+
+// silentMP3 returns an MP3 of n silent frames, 1152 samples each: every
+// frame is a header followed by zeroed side info and main data.
+func silentMP3(n int) []byte {
+	frame := make([]byte, mp3FrameSize)
+	copy(frame, []byte{0xFF, 0xFB, 0x90, 0x00})
+	return bytes.Repeat(frame, n)
+}
+
+// silentMP3Duration is how long silentMP3(n) plays.
+func silentMP3Duration(n int) time.Duration {
+	return sampleRate.D(n * 1152)
+}
+
+// serveBytes serves data as a stream, calling wait, if set, before sending it.
+func serveBytes(t *testing.T, data []byte, wait func()) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if wait != nil {
+			wait()
+		}
+		w.Header().Set("Content-Type", "audio/mpeg")
+		w.Write(data)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// newTestPlayer returns a player that never opens the audio device, so
+// tests run without one; nothing pulls audio from the speaker.
+func newTestPlayer(t *testing.T, cfg Config) *Player {
+	t.Helper()
+	p := &Player{
+		logger: slog.New(slog.DiscardHandler),
+		http:   http.DefaultClient,
+		cfg:    cfg,
+		done:   make(chan uint64, 1),
+		level:  100,
+	}
+	t.Cleanup(p.Stop)
+	return p
+}
+
+// waitDownloaded waits for the current stream to finish downloading and
+// its seeking decoder to be ready.
+func waitDownloaded(t *testing.T, p *Player) {
+	t.Helper()
+	p.mu.Lock()
+	streamer := p.streamer
+	p.mu.Unlock()
+	<-streamer.prepared
+	if !streamer.buf.Complete() {
+		t.Fatal("stream didn't download completely")
+	}
+}
+
+func TestSeekMP3(t *testing.T) {
+	const frames = 400
+	srv := serveBytes(t, silentMP3(frames), nil)
+	p := newTestPlayer(t, DefaultConfig())
+
+	if _, err := p.Play(srv.URL, "mp3", NowPlaying{Title: "silence"}); err != nil {
+		t.Fatalf("Play: %v", err)
+	}
+	if !p.Seekable() {
+		t.Fatal("Seekable() = false for a stream that fits the seek buffer")
+	}
+	waitDownloaded(t, p)
+
+	pos, err := p.Seek(5 * time.Second)
+	if err != nil {
+		t.Fatalf("Seek: %v", err)
+	}
+	if pos != 5*time.Second {
+		t.Errorf("Seek(5s) = %v, want 5s", pos)
+	}
+	if pos, _ := p.Seek(-2 * time.Second); pos != 3*time.Second {
+		t.Errorf("Seek back 2s = %v, want 3s", pos)
+	}
+	if pos, _ := p.Seek(-time.Minute); pos != 0 {
+		t.Errorf("Seek before the start = %v, want 0", pos)
+	}
+	end := silentMP3Duration(frames)
+	if pos, _ := p.Seek(time.Hour); pos <= end-time.Second || pos > end {
+		t.Errorf("Seek past the end = %v, want just short of %v", pos, end)
+	}
+}
+
+func TestResumeRewind(t *testing.T) {
+	srv := serveBytes(t, silentMP3(400), nil)
+	cfg := DefaultConfig()
+	cfg.ResumeRewindSec = 3
+	p := newTestPlayer(t, cfg)
+
+	if _, err := p.Play(srv.URL, "mp3", NowPlaying{Title: "silence"}); err != nil {
+		t.Fatalf("Play: %v", err)
+	}
+	waitDownloaded(t, p)
+
+	// Pausing leaves the position alone; resuming rewinds.
+	p.Seek(5 * time.Second)
+	p.TogglePause()
+	if got := p.Elapsed(); got != 5 {
+		t.Errorf("paused at %vs, want 5s", got)
+	}
+	p.TogglePause()
+	if got := p.Elapsed(); got != 2 {
+		t.Errorf("resumed at %vs, want 2s", got)
+	}
+
+	// Near the start it stops at the top of the track.
+	p.Seek(-time.Second)
+	p.TogglePause()
+	p.TogglePause()
+	if got := p.Elapsed(); got != 0 {
+		t.Errorf("resumed 1s in at %vs, want 0", got)
+	}
+}
+
+func TestSeekWhileBuffering(t *testing.T) {
+	data := silentMP3(400)
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Send enough to start playing, then hold the rest back.
+		w.Write(data[:len(data)/2])
+		w.(http.Flusher).Flush()
+		<-release
+		w.Write(data[len(data)/2:])
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(release) })
+
+	cfg := DefaultConfig()
+	cfg.PrebufferMs = 0
+	p := newTestPlayer(t, cfg)
+	if _, err := p.Play(srv.URL, "mp3", NowPlaying{Title: "silence"}); err != nil {
+		t.Fatalf("Play: %v", err)
+	}
+	if _, err := p.Seek(time.Second); !errors.Is(err, ErrBuffering) {
+		t.Errorf("Seek while downloading: err = %v, want ErrBuffering", err)
+	}
+}
+
+func TestSeekTooBigToKeep(t *testing.T) {
+	srv := serveBytes(t, silentMP3(400), nil)
+	cfg := DefaultConfig()
+	cfg.SeekBufferMB = 0
+	p := newTestPlayer(t, cfg)
+
+	if _, err := p.Play(srv.URL, "mp3", NowPlaying{Title: "silence"}); err != nil {
+		t.Fatalf("Play: %v", err)
+	}
+	if p.Seekable() {
+		t.Error("Seekable() = true with the seek buffer off")
+	}
+	if _, err := p.Seek(time.Second); !errors.Is(err, ErrNotSeekable) {
+		t.Errorf("Seek: err = %v, want ErrNotSeekable", err)
+	}
+}
+
+func TestStreamBufferSeek(t *testing.T) {
+	data := []byte("0123456789")
+	b := newStreamBuffer(io.NopCloser(bytes.NewReader(data)), 1<<10)
+	defer b.Close()
+	if !b.WaitComplete() {
+		t.Fatal("WaitComplete() = false")
+	}
+
+	for _, tc := range []struct {
+		offset int64
+		whence int
+		want   string
+	}{
+		{4, io.SeekStart, "4567"},
+		{-2, io.SeekCurrent, "6789"},
+		{-3, io.SeekEnd, "789"},
+	} {
+		if _, err := b.Seek(tc.offset, tc.whence); err != nil {
+			t.Fatalf("Seek(%d, %d): %v", tc.offset, tc.whence, err)
+		}
+		got := make([]byte, len(tc.want))
+		if _, err := io.ReadFull(b, got); err != nil || string(got) != tc.want {
+			t.Errorf("after Seek(%d, %d) read %q, %v; want %q", tc.offset, tc.whence, got, err, tc.want)
+		}
+	}
+}
+
+// stoppedDecoder is a decoder that has stopped on err.
+type stoppedDecoder struct{ err error }
+
+func (d stoppedDecoder) Stream([][2]float64) (int, bool) { return 0, false }
+func (d stoppedDecoder) Err() error                      { return d.err }
+func (d stoppedDecoder) Len() int                        { return 0 }
+func (d stoppedDecoder) Position() int                   { return 0 }
+func (d stoppedDecoder) Seek(int) error                  { return nil }
+func (d stoppedDecoder) Close() error                    { return nil }
+
+func TestStreamEndErr(t *testing.T) {
+	errBadFrame := errors.New("bad frame")
+	errNetwork := errors.New("connection reset")
+	tests := []struct {
+		name       string
+		decodeErr  error
+		downloaded error // what the download ends with after its data
+		want       error
+		wantDecode bool
+	}{
+		{"played through", nil, io.EOF, nil, false},
+		{"bad data", errBadFrame, io.EOF, errBadFrame, true},
+		{"cut short", io.ErrUnexpectedEOF, io.EOF, io.ErrUnexpectedEOF, false},
+		{"download failed", errBadFrame, errNetwork, errNetwork, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := io.MultiReader(bytes.NewReader(silentMP3(10)), iotest.ErrReader(tt.downloaded))
+			buf := newStreamBuffer(io.NopCloser(src), 1<<20)
+			defer buf.Close()
+			buf.WaitComplete()
+
+			s := &seekStreamer{StreamSeekCloser: stoppedDecoder{tt.decodeErr}, buf: buf, codec: "mp3", rate: sampleRate}
+			err := s.endErr()
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Errorf("endErr() = %v, want %v", err, tt.want)
+			}
+			if errors.Is(err, ErrDecode) != tt.wantDecode {
+				t.Errorf("endErr() = %v; wraps ErrDecode = %v, want %v", err, !tt.wantDecode, tt.wantDecode)
+			}
+		})
+	}
+}
+
+func TestPlayAtMP3(t *testing.T) {
+	srv := serveBytes(t, silentMP3(400), nil)
+
+	p := newTestPlayer(t, DefaultConfig())
+	if _, err := p.PlayAt(srv.URL, "mp3", NowPlaying{Title: "episode"}, 7*time.Second); err != nil {
+		t.Fatalf("PlayAt: %v", err)
+	}
+	if got := p.Elapsed(); got != 7 {
+		t.Errorf("Elapsed() = %v after PlayAt(7s), want 7", got)
+	}
+
+	// A stream that can't seek plays from the top instead.
+	cfg := DefaultConfig()
+	cfg.SeekBufferMB = 0
+	p = newTestPlayer(t, cfg)
+	if _, err := p.PlayAt(srv.URL, "mp3", NowPlaying{Title: "episode"}, 7*time.Second); err != nil {
+		t.Fatalf("PlayAt without seeking: %v", err)
+	}
+	if got := p.Elapsed(); got != 0 {
+		t.Errorf("Elapsed() = %v after PlayAt on an unseekable stream, want 0", got)
+	}
+}
+
+func TestOverlappingPlays(t *testing.T) {
+	const plays = 4
+	arrived := make(chan struct{})
+	release := make(chan struct{})
+	srv := serveBytes(t, silentMP3(100), func() {
+		arrived <- struct{}{}
+		<-release
+	})
+	p := newTestPlayer(t, DefaultConfig())
+
+	// Each Play starts while the one before is still waiting on the server.
+	type result struct {
+		gen uint64
+		err error
+	}
+	results := make(chan result, plays)
+	for range plays {
+		go func() {
+			gen, err := p.Play(srv.URL, "mp3", NowPlaying{Title: "silence"})
+			results <- result{gen, err}
+		}()
+		<-arrived
+	}
+	close(release)
+
+	superseded := 0
+	var gen uint64
+	for range plays {
+		switch r := <-results; {
+		case errors.Is(r.err, ErrSuperseded):
+			superseded++
+		case r.err != nil:
+			t.Fatalf("Play: %v", r.err)
+		default:
+			gen = r.gen
+		}
+	}
+	if superseded != plays-1 {
+		t.Fatalf("%d plays superseded, want %d", superseded, plays-1)
+	}
+	if got := p.Generation(); gen != got {
+		t.Fatalf("Play returned generation %d, playing %d", gen, got)
+	}
+
+	// Only the playing track's end is signalled.
+	p.ended(gen - 1)
+	select {
+	case got := <-p.Done():
+		t.Fatalf("Done() signalled generation %d for a replaced track", got)
+	default:
+	}
+	p.ended(gen)
+	select {
+	case got := <-p.Done():
+		if got != gen {
+			t.Errorf("Done() = %d, want %d", got, gen)
+		}
+	default:
+		t.Error("Done() didn't signal the playing track's end")
+	}
+}
+
+// connCounter counts a test server's open connections.
+type connCounter struct {
+	mu   sync.Mutex
+	open map[net.Conn]bool
+}
+
+func (c *connCounter) track(conn net.Conn, state http.ConnState) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch state {
+	case http.StateNew:
+		c.open[conn] = true
+	case http.StateClosed, http.StateHijacked:
+		delete(c.open, conn)
+	}
+}
+
+// waitClosed waits for every connection to close, failing after a while.
+func (c *connCounter) waitClosed(t *testing.T) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		c.mu.Lock()
+		n := len(c.open)
+		c.mu.Unlock()
+		if n == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d connections still open", n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestManyPlays(t *testing.T) {
+	data := silentMP3(2000)
+	conns := &connCounter{open: make(map[net.Conn]bool)}
+	done := make(chan struct{})
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Don't finish until the client goes, like a long track on a slow
+		// link: a body read to the end frees its connection either way.
+		w.Write(data)
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	srv.Config.ConnState = conns.track
+	srv.Start()
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(done) })
+
+	p := newTestPlayer(t, DefaultConfig())
+	p.http = srv.Client()
+
+	var wg sync.WaitGroup
+	var started atomic.Int32
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if i%10 == 9 {
+				p.Stop()
+			}
+			_, err := p.Play(srv.URL, "mp3", NowPlaying{Title: "silence"})
+			switch {
+			case err == nil:
+				started.Add(1)
+			case !errors.Is(err, ErrSuperseded):
+				t.Errorf("Play: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if started.Load() == 0 {
+		t.Error("no Play started")
+	}
+
+	// Every body is closed once playback stops, so the client has nothing
+	// in use and drops what it kept idle.
+	p.Stop()
+	srv.Client().CloseIdleConnections()
+	conns.waitClosed(t)
+}
+
+func TestStopClosesConnection(t *testing.T) {
+	data := silentMP3(100)
+	closed := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+		w.(http.Flusher).Flush()
+		// The client going away cancels the request.
+		<-r.Context().Done()
+		close(closed)
+	}))
+	t.Cleanup(srv.Close)
+
+	p := newTestPlayer(t, DefaultConfig())
+	if _, err := p.Play(srv.URL, "mp3", NowPlaying{Title: "silence"}); err != nil {
+		t.Fatalf("Play: %v", err)
+	}
+	select {
+	case <-closed:
+		t.Fatal("connection closed while playing")
+	default:
+	}
+
+	p.Stop()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("connection still open after Stop")
+	}
+}
+
+func TestPlayErrorHidesCredentials(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	p := newTestPlayer(t, DefaultConfig())
+	_, err := p.Play(srv.URL+"/rest/stream.view?id=1&u=alice&p=hunter2", "mp3", NowPlaying{Title: "silence"})
+	if err == nil {
+		t.Fatal("Play succeeded against a closed server")
+	}
+	if strings.Contains(err.Error(), "hunter2") || strings.Contains(err.Error(), "alice") {
+		t.Errorf("error quotes the credentials: %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package player
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gopxl/beep/v2"
+)
+
+// seekStreamer decodes a buffered stream as it arrives and, once the whole
+// stream is in memory, switches to a decoder reading the buffer as a seeker
+// so it can seek. Decoding that way from the start would hold up playback:
+// the MP3 decoder reads the whole stream up front to index its frames. For
+// the same reason the seeking decoder is built in the background as soon
+// as the download completes, and only swapped in under the speaker lock.
+type seekStreamer struct {
+	beep.StreamSeekCloser
+	buf    *streamBuffer
+	codec  string          // format name the stream is decoded as
+	rate   beep.SampleRate // source sample rate
+	seeker bool            // the decoder reads buf as a seeker
+
+	mu       sync.Mutex
+	next     beep.StreamSeekCloser // seeking decoder waiting to be swapped in
+	nextErr  error                 // why there's no seeking decoder
+	prepared chan struct{}         // closed once prepare has finished
+}
+
+// newSeekStreamer wraps dec, which is decoding buf as codec at rate, and
+// starts preparing its seeking decoder.
+func newSeekStreamer(dec beep.StreamSeekCloser, buf *streamBuffer, codec string, rate beep.SampleRate) *seekStreamer {
+	s := &seekStreamer{StreamSeekCloser: dec, buf: buf, codec: codec, rate: rate, prepared: make(chan struct{})}
+	go s.prepare()
+	return s
+}
+
+// prepare waits for the whole stream and builds the seeking decoder over
+// it. It gives up if the stream fails, gets too big to keep, or closes.
+func (s *seekStreamer) prepare() {
+	defer close(s.prepared)
+	if !s.buf.WaitComplete() {
+		return
+	}
+	// The decoder gets a reader of its own: the playing decoder is still
+	// reading buf, and the stream stays in memory for as long as buf does.
+	dec, _, err := decode(nopCloser{bytes.NewReader(s.buf.Bytes())}, s.codec)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.next, s.nextErr = dec, err
+}
+
+// Seekable reports whether the stream can seek once it's fully buffered.
+func (s *seekStreamer) Seekable() bool {
+	return s.seeker || s.buf.Kept()
+}
+
+// ready switches to the seeking decoder, if it hasn't already. The new
+// decoder starts from the top, so callers seek straight after.
+func (s *seekStreamer) ready() error {
+	if s.seeker {
+		return nil
+	}
+	if !s.buf.Kept() {
+		return ErrNotSeekable
+	}
+	s.mu.Lock()
+	dec, err := s.next, s.nextErr
+	s.next = nil
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNotSeekable, err)
+	}
+	if dec == nil {
+		return ErrBuffering
+	}
+	// The old decoder's Close would close the buffer, so it's just dropped.
+	s.StreamSeekCloser = dec
+	s.seeker = true
+	return nil
+}
+
+// Seek moves to sample p of the source, switching decoders first if need be.
+func (s *seekStreamer) Seek(p int) error {
+	if err := s.ready(); err != nil {
+		return err
+	}
+	return s.StreamSeekCloser.Seek(p)
+}
+
+// SeekTo moves to target, clamped to the start and end of the track, and
+// returns where it landed.
+func (s *seekStreamer) SeekTo(target time.Duration) (time.Duration, error) {
+	if err := s.ready(); err != nil {
+		return 0, err
+	}
+	target = max(target, 0)
+	if n := s.Len(); n > 0 {
+		target = min(target, s.rate.D(n-1))
+	}
+	if err := s.StreamSeekCloser.Seek(s.rate.N(target)); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrNotSeekable, err)
+	}
+	return target, nil
+}
+
+// endErr returns what stopped the decoder before the end of the stream: the
+// download's error if it failed, or an error wrapping ErrDecode for data
+// the decoder gave up on. A stream that was merely cut short ends without
+// ErrDecode.
+func (s *seekStreamer) endErr() error {
+	err := s.Err()
+	if err == nil {
+		return nil
+	}
+	if bufErr := s.buf.Err(); bufErr != nil {
+		return bufErr
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrDecode, err)
+}
+
+// nopCloser is a ReadSeeker with a Close that does nothing.
+type nopCloser struct {
+	io.ReadSeeker
+}
+
+func (nopCloser) Close() error { return nil }
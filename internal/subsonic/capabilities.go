@@ -0,0 +1,70 @@
+package subsonic
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// ErrNotSupported is returned when the server doesn't implement an endpoint.
+var ErrNotSupported = errors.New("not supported by server")
+
+// Capability is an optional endpoint that not every server implements.
+type Capability string
+
+const (
+	CapSimilarSongs Capability = "getSimilarSongs2"
+	CapTopSongs     Capability = "getTopSongs"
+	CapAlbumList    Capability = "getAlbumList2"
+	CapPlaylists    Capability = "getPlaylists"
+	CapBookmarks    Capability = "getBookmarks"
+	CapPodcasts     Capability = "getPodcasts"
+)
+
+// optionalCapabilities lists every capability ProbeCapabilities checks.
+var optionalCapabilities = []Capability{
+	CapSimilarSongs, CapTopSongs, CapAlbumList, CapPlaylists, CapBookmarks, CapPodcasts,
+}
+
+// Supports reports whether the server implements a capability. Capabilities
+// that haven't been probed or failed yet are assumed to be supported.
+func (c *Client) Supports(capability Capability) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	supported, known := c.caps[capability]
+	return !known || supported
+}
+
+// ProbeCapabilities calls each optional endpoint with minimal parameters and
+// records which ones the server implements. A "missing parameter" error still
+// proves the endpoint exists, so only not-implemented responses count against it.
+func (c *Client) ProbeCapabilities() {
+	for _, capability := range optionalCapabilities {
+		var resp pingResponse
+		err := c.get(string(capability), url.Values{}, &resp)
+		if errors.Is(err, ErrNotSupported) {
+			continue // get already recorded it
+		}
+		supported := err == nil && !notImplemented(resp.Response.Error)
+		c.setCapability(capability, supported)
+	}
+}
+
+// setCapability records whether the server implements a capability.
+func (c *Client) setCapability(capability Capability, supported bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.caps == nil {
+		c.caps = make(map[Capability]bool)
+	}
+	c.caps[capability] = supported
+}
+
+// notImplemented reports whether an API error means the endpoint is missing.
+func notImplemented(e *APIError) bool {
+	if e == nil {
+		return false
+	}
+	msg := strings.ToLower(e.Message)
+	return strings.Contains(msg, "not implemented") || strings.Contains(msg, "not supported")
+}
@@ -1,35 +1,157 @@
 package subsonic
 
 import (
+	"compress/gzip"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
 const (
 	apiVersion = "1.16.1"
 	clientName = "kitsune"
+
+	defaultTimeout = 30 * time.Second
 )
 
+// Options tunes the client's HTTP behavior. Zero values use defaults.
+type Options struct {
+	// Timeout bounds each API and cover art request. Streams are exempt.
+	Timeout time.Duration
+	// CACertPath is a PEM file of extra certificates to trust, e.g. for a
+	// self-signed reverse proxy.
+	CACertPath string
+	// ProxyURL routes all requests through an HTTP(S) proxy. Empty uses
+	// the environment (HTTP_PROXY, HTTPS_PROXY, NO_PROXY).
+	ProxyURL string
+	// InsecureSkipVerify disables TLS certificate verification entirely.
+	InsecureSkipVerify bool
+	// Logger receives per-request debug logs (URL with credentials
+	// redacted, status, timing) and decode failures. Nil uses slog.Default.
+	Logger *slog.Logger
+}
+
 // Client talks to a Subsonic-compatible server (Navidrome, etc.).
 type Client struct {
 	baseURL  string
 	user     string
 	password string
 	http     *http.Client
+	logger   *slog.Logger
+	server   ServerInfo // populated by Ping
+
+	mu   sync.Mutex
+	caps map[Capability]bool // endpoint → implemented; absent means unknown
+}
+
+// ServerInfo identifies the server implementation, as reported by ping.
+type ServerInfo struct {
+	Type          string // e.g. "navidrome"; empty for servers that don't report it
+	ServerVersion string
+	APIVersion    string
+	OpenSubsonic  bool
+}
+
+// String renders the server as e.g. "navidrome 0.53.3 (API 1.16.1)".
+func (s ServerInfo) String() string {
+	name := s.Type
+	if name == "" {
+		name = "subsonic"
+	}
+	if s.ServerVersion != "" {
+		name += " " + s.ServerVersion
+	}
+	if s.APIVersion != "" {
+		name += " (API " + s.APIVersion + ")"
+	}
+	return name
 }
 
 // NewClient creates a Subsonic API client.
-func NewClient(baseURL, user, password string) *Client {
+func NewClient(baseURL, user, password string, opts Options) (*Client, error) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultTimeout
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	transport, err := newTransport(opts)
+	if err != nil {
+		return nil, err
+	}
 	return &Client{
 		baseURL:  baseURL,
 		user:     user,
 		password: password,
-		http:     &http.Client{Timeout: 30 * time.Second},
+		http:     &http.Client{Transport: transport, Timeout: opts.Timeout},
+		logger:   opts.Logger.With("component", "subsonic"),
+	}, nil
+}
+
+// newTransport returns a transport tuned for many small requests to a single
+// host: sync and art fetches reuse kept-alive connections instead of paying
+// for a TLS handshake each time.
+func newTransport(opts Options) (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConns = 32
+	t.MaxIdleConnsPerHost = 8
+	t.IdleConnTimeout = 90 * time.Second
+
+	if opts.ProxyURL != "" {
+		proxy, err := url.Parse(opts.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("parsing proxy URL: %w", err)
+		}
+		t.Proxy = http.ProxyURL(proxy)
+	}
+
+	if opts.CACertPath != "" || opts.InsecureSkipVerify {
+		tlsConfig := &tls.Config{InsecureSkipVerify: opts.InsecureSkipVerify}
+		if opts.CACertPath != "" {
+			pool, err := loadCertPool(opts.CACertPath)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.RootCAs = pool
+		}
+		t.TLSClientConfig = tlsConfig
+	}
+
+	return t, nil
+}
+
+// loadCertPool returns the system roots plus the certificates in a PEM file.
+func loadCertPool(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA certificate: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
 	}
+	return pool, nil
+}
+
+// StreamHTTPClient returns an HTTP client for audio streaming. It shares the
+// API client's transport (TLS settings, connection pooling) but has no overall
+// timeout, since a stream body is read for the full length of a track.
+func (c *Client) StreamHTTPClient() *http.Client {
+	return &http.Client{Transport: c.http.Transport}
 }
 
 // StreamURL returns the URL to stream a track by ID.
@@ -51,7 +173,8 @@ func (c *Client) CoverArtURL(id string, size int) string {
 	return c.buildURL("getCoverArt", params)
 }
 
-// Ping tests the connection and authentication.
+// Ping tests the connection and authentication, recording the server's
+// identity for Server.
 func (c *Client) Ping() error {
 	var resp pingResponse
 	if err := c.get("ping", nil, &resp); err != nil {
@@ -60,9 +183,25 @@ func (c *Client) Ping() error {
 	if resp.Response.Status != "ok" {
 		return fmt.Errorf("ping failed: %s", resp.Response.Status)
 	}
+	c.server = ServerInfo{
+		Type:          resp.Response.Type,
+		ServerVersion: resp.Response.ServerVersion,
+		APIVersion:    resp.Response.Version,
+		OpenSubsonic:  resp.Response.OpenSubsonic,
+	}
 	return nil
 }
 
+// BaseURL returns the server URL the client was created with.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// Server returns the server identity reported by the last successful Ping.
+func (c *Client) Server() ServerInfo {
+	return c.server
+}
+
 // GetArtists returns all artists from the library, indexed alphabetically.
 func (c *Client) GetArtists() ([]Artist, error) {
 	var resp artistsResponse
@@ -104,6 +243,41 @@ func (c *Client) GetAlbum(id string) (*AlbumDetail, error) {
 	return &resp.Response.Album, nil
 }
 
+// GetSimilarSongs2 returns up to count songs similar to an artist's, drawn
+// from across the library.
+func (c *Client) GetSimilarSongs2(artistID string, count int) ([]Song, error) {
+	var resp similarSongsResponse
+	params := url.Values{"id": {artistID}, "count": {strconv.Itoa(count)}}
+	if err := c.get(string(CapSimilarSongs), params, &resp); err != nil {
+		return nil, fmt.Errorf("getSimilarSongs2(%s): %w", artistID, err)
+	}
+	if resp.Response.Status != "ok" {
+		if notImplemented(resp.Response.Error) {
+			c.setCapability(CapSimilarSongs, false)
+		}
+		return nil, apiErr(resp.Response.Error)
+	}
+	return resp.Response.SimilarSongs.Song, nil
+}
+
+// GetTopSongs returns up to count of an artist's most popular songs. The
+// endpoint keys on the artist's name, not ID, as last.fm reports it.
+func (c *Client) GetTopSongs(artistName string, count int) ([]Song, error) {
+	var resp topSongsResponse
+	params := url.Values{"artist": {artistName}, "count": {strconv.Itoa(count)}}
+	if err := c.get(string(CapTopSongs), params, &resp); err != nil {
+		return nil, fmt.Errorf("getTopSongs(%s): %w", artistName, err)
+	}
+	if resp.Response.Status != "ok" {
+		if notImplemented(resp.Response.Error) {
+			c.setCapability(CapTopSongs, false)
+			return nil, fmt.Errorf("getTopSongs: %w", ErrNotSupported)
+		}
+		return nil, apiErr(resp.Response.Error)
+	}
+	return resp.Response.TopSongs.Song, nil
+}
+
 // NowPlaying reports a track as currently being listened to.
 func (c *Client) NowPlaying(id string) error {
 	var resp pingResponse
@@ -136,23 +310,94 @@ func (c *Client) buildURL(endpoint string, params url.Values) string {
 	return fmt.Sprintf("%s/rest/%s.view?%s", c.baseURL, endpoint, params.Encode())
 }
 
+// fetch issues a GET and logs it at debug level with credentials redacted.
+func (c *Client) fetch(rawURL string) (*http.Response, error) {
+	start := time.Now()
+	resp, err := c.http.Get(rawURL)
+	if err != nil {
+		// The error quotes the URL, credentials and all, and it goes on to
+		// callers' logs and the status bar as well as this one.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = redactURL(urlErr.URL)
+		}
+		c.logger.Debug("request failed", "url", redactURL(rawURL), "error", err,
+			"elapsed", time.Since(start).Round(time.Millisecond))
+		return nil, err
+	}
+	c.logger.Debug("request", "url", redactURL(rawURL), "status", resp.StatusCode,
+		"elapsed", time.Since(start).Round(time.Millisecond))
+	return resp, nil
+}
+
 func (c *Client) get(endpoint string, params url.Values, dest any) error {
-	resp, err := c.http.Get(c.buildURL(endpoint, params))
+	resp, err := c.fetch(c.buildURL(endpoint, params))
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
+	// Servers answer unknown endpoints with 404 or 501; remember so the UI
+	// can hide features that would only ever fail.
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusNotImplemented {
+		c.setCapability(Capability(endpoint), false)
+		return fmt.Errorf("%s: %w", endpoint, ErrNotSupported)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("unexpected status: %d", resp.StatusCode)
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := readBody(resp)
 	if err != nil {
 		return fmt.Errorf("reading response: %w", err)
 	}
 
-	return json.Unmarshal(body, dest)
+	if err := json.Unmarshal(body, dest); err != nil {
+		c.logger.Warn("decoding response failed", "endpoint", endpoint, "error", err,
+			"body", snippet(body, 512))
+		return fmt.Errorf("decoding %s response: %w", endpoint, err)
+	}
+	return nil
+}
+
+// readBody reads a response body, decompressing it if it is still gzipped.
+// The transport only decodes gzip it asked for itself, so a reverse proxy
+// that compresses unprompted would otherwise hand us raw gzip bytes.
+func readBody(resp *http.Response) ([]byte, error) {
+	var r io.Reader = resp.Body
+	if !resp.Uncompressed && strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("opening gzip body: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+	return io.ReadAll(r)
+}
+
+// redactURL masks credential query parameters so URLs are safe to log.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "(unparseable URL)"
+	}
+	q := u.Query()
+	for _, key := range []string{"u", "p", "t", "s"} {
+		if q.Has(key) {
+			q.Set(key, "REDACTED")
+		}
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// snippet returns up to n bytes of body for logging.
+func snippet(body []byte, n int) string {
+	if len(body) > n {
+		return string(body[:n]) + "…"
+	}
+	return string(body)
 }
 
 func apiErr(e *APIError) error {
@@ -192,6 +437,7 @@ type Album struct {
 	Duration  int    `json:"duration"` // seconds
 	Year      int    `json:"year"`
 	Genre     string `json:"genre"`
+	Created   string `json:"created"` // ISO 8601, when the server added it
 }
 
 type AlbumDetail struct {
@@ -220,6 +466,30 @@ type Song struct {
 	BitRate    int    `json:"bitRate"`
 	Suffix     string `json:"suffix"` // file extension (mp3, flac, etc.)
 	CoverArt   string `json:"coverArt"`
+	IsDir      bool   `json:"isDir"`
+	IsVideo    bool   `json:"isVideo"`
+	Type       string `json:"type"` // "music", "podcast", "audiobook" or "video"
+}
+
+// videoSuffixes are file extensions of video files some servers list
+// among songs.
+var videoSuffixes = map[string]bool{
+	"avi": true, "flv": true, "m4v": true, "mkv": true, "mov": true,
+	"mp4": true, "mpeg": true, "mpg": true, "webm": true, "wmv": true,
+}
+
+// IsAudio reports whether the entry is something to play: not a directory,
+// a video, or an empty placeholder without a duration or file type.
+func (s Song) IsAudio() bool {
+	switch {
+	case s.IsDir, s.IsVideo, s.Type == "video":
+		return false
+	case videoSuffixes[strings.ToLower(s.Suffix)]:
+		return false
+	case s.Duration == 0 && s.Suffix == "":
+		return false
+	}
+	return true
 }
 
 // --- JSON response envelopes ---
@@ -227,6 +497,11 @@ type Song struct {
 type baseResponse struct {
 	Status string   `json:"status"`
 	Error  *APIError `json:"error,omitempty"`
+	// Server identity, present on every response.
+	Version       string `json:"version"`
+	Type          string `json:"type"`
+	ServerVersion string `json:"serverVersion"`
+	OpenSubsonic  bool   `json:"openSubsonic"`
 }
 
 type pingResponse struct {
@@ -258,3 +533,21 @@ type albumResponse struct {
 		Album AlbumDetail `json:"album"`
 	} `json:"subsonic-response"`
 }
+
+type topSongsResponse struct {
+	Response struct {
+		baseResponse
+		TopSongs struct {
+			Song []Song `json:"song"`
+		} `json:"topSongs"`
+	} `json:"subsonic-response"`
+}
+
+type similarSongsResponse struct {
+	Response struct {
+		baseResponse
+		SimilarSongs struct {
+			Song []Song `json:"song"`
+		} `json:"similarSongs2"`
+	} `json:"subsonic-response"`
+}
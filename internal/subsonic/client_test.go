@@ -0,0 +1,85 @@
+package subsonic
+
+import (
+	"bytes"
+	"compress/gzip"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestReadBodyGzip(t *testing.T) {
+	const body = `{"subsonic-response":{"status":"ok"}}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("plain") != "" {
+			w.Write([]byte(body))
+			return
+		}
+		// Compress whether or not the client asked, as some proxies do.
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte(body))
+		gz.Close()
+	}))
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		name string
+		path string
+		// DisableCompression stops the transport asking for gzip, and so
+		// decoding it: the body arrives still compressed.
+		transport *http.Transport
+	}{
+		{"gzip the transport asked for", "/", &http.Transport{}},
+		{"unprompted gzip", "/", &http.Transport{DisableCompression: true}},
+		{"uncompressed", "/?plain=1", &http.Transport{DisableCompression: true}},
+	} {
+		client := &http.Client{Transport: tc.transport}
+		resp, err := client.Get(srv.URL + tc.path)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		got, err := readBody(resp)
+		resp.Body.Close()
+		if err != nil {
+			t.Errorf("%s: readBody: %v", tc.name, err)
+		} else if string(got) != body {
+			t.Errorf("%s: readBody = %q, want %q", tc.name, got, body)
+		}
+		tc.transport.CloseIdleConnections()
+	}
+}
+
+func TestFailedRequestRedactsCredentials(t *testing.T) {
+	// A server that's gone, so the request fails with a *url.Error.
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c, err := NewClient(srv.URL, "alice", "hunter2", Options{Logger: logger})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	creds, _ := url.Parse(c.buildURL("ping", nil))
+
+	err = c.Ping()
+	if err == nil {
+		t.Fatal("Ping succeeded against a closed server")
+	}
+	for _, key := range []string{"u", "p"} {
+		v := url.QueryEscape(creds.Query().Get(key))
+		if strings.Contains(err.Error(), v) {
+			t.Errorf("error quotes %s=%s: %v", key, v, err)
+		}
+		if strings.Contains(logs.String(), v) {
+			t.Errorf("log quotes %s=%s: %s", key, v, logs.String())
+		}
+	}
+	if !strings.Contains(err.Error(), "REDACTED") {
+		t.Errorf("error lost its URL rather than redacting it: %v", err)
+	}
+}
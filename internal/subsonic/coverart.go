@@ -1,24 +1,40 @@
 package subsonic
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"strings"
 )
 
+// ErrNoCoverArt is wrapped by GetCoverArt's error when the server refuses or
+// doesn't know the art ID, as opposed to a network failure. Servers differ on
+// whether they want an album's coverArt ID or the album ID itself.
+var ErrNoCoverArt = errors.New("no cover art for id")
+
 // GetCoverArt fetches cover art bytes for the given ID.
 // Size is the desired dimension in pixels (square). Use 0 for original size.
 func (c *Client) GetCoverArt(id string, size int) ([]byte, error) {
 	artURL := c.CoverArtURL(id, size)
 
-	resp, err := c.http.Get(artURL)
+	resp, err := c.fetch(artURL)
 	if err != nil {
 		return nil, fmt.Errorf("fetching cover art: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+		return nil, fmt.Errorf("cover art returned status %d: %w", resp.StatusCode, ErrNoCoverArt)
+	default:
 		return nil, fmt.Errorf("cover art returned status %d", resp.StatusCode)
 	}
+	// Some servers answer a bad ID with a 200 and an API error body.
+	if ct := resp.Header.Get("Content-Type"); strings.Contains(ct, "json") || strings.Contains(ct, "xml") {
+		return nil, fmt.Errorf("cover art returned %s: %w", ct, ErrNoCoverArt)
+	}
 
 	data, err := io.ReadAll(resp.Body)
 	if err != nil {
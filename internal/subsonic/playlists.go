@@ -0,0 +1,83 @@
+package subsonic
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// Playlist is a server-side playlist.
+type Playlist struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Owner     string `json:"owner"`
+	SongCount int    `json:"songCount"`
+	Duration  int    `json:"duration"` // seconds
+}
+
+type playlistsResponse struct {
+	Response struct {
+		baseResponse
+		Playlists struct {
+			Playlist []Playlist `json:"playlist"`
+		} `json:"playlists"`
+	} `json:"subsonic-response"`
+}
+
+// GetPlaylists returns the playlists visible to the user.
+func (c *Client) GetPlaylists() ([]Playlist, error) {
+	var resp playlistsResponse
+	if err := c.get(string(CapPlaylists), nil, &resp); err != nil {
+		return nil, fmt.Errorf("getPlaylists: %w", err)
+	}
+	if resp.Response.Status != "ok" {
+		return nil, apiErr(resp.Response.Error)
+	}
+	return resp.Response.Playlists.Playlist, nil
+}
+
+// UpdatePlaylist appends songs to a playlist and removes the entries at the
+// given zero-based positions. Positions refer to the playlist before the call.
+func (c *Client) UpdatePlaylist(id string, addSongIDs []string, removeIndexes []int) error {
+	params := url.Values{"playlistId": {id}}
+	for _, songID := range addSongIDs {
+		params.Add("songIdToAdd", songID)
+	}
+	for _, idx := range removeIndexes {
+		params.Add("songIndexToRemove", strconv.Itoa(idx))
+	}
+
+	var resp pingResponse
+	if err := c.get("updatePlaylist", params, &resp); err != nil {
+		return fmt.Errorf("updatePlaylist(%s): %w", id, err)
+	}
+	if resp.Response.Status != "ok" {
+		return apiErr(resp.Response.Error)
+	}
+	return nil
+}
+
+// PlaylistDetail is a playlist with its entries in order.
+type PlaylistDetail struct {
+	Playlist
+	Entry []Song `json:"entry"`
+}
+
+type playlistResponse struct {
+	Response struct {
+		baseResponse
+		Playlist PlaylistDetail `json:"playlist"`
+	} `json:"subsonic-response"`
+}
+
+// GetPlaylist returns a playlist and its entries.
+func (c *Client) GetPlaylist(id string) (*PlaylistDetail, error) {
+	var resp playlistResponse
+	if err := c.get("getPlaylist", url.Values{"id": {id}}, &resp); err != nil {
+		return nil, fmt.Errorf("getPlaylist(%s): %w", id, err)
+	}
+	if resp.Response.Status != "ok" {
+		return nil, apiErr(resp.Response.Error)
+	}
+	return &resp.Response.Playlist, nil
+}
@@ -0,0 +1,170 @@
+package subsonic
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+)
+
+// PodcastChannel is a podcast the server subscribes to.
+type PodcastChannel struct {
+	ID          string           `json:"id"`
+	URL         string           `json:"url"`
+	Title       string           `json:"title"`
+	Description string           `json:"description"`
+	CoverArt    string           `json:"coverArt"`
+	Status      string           `json:"status"`
+	Episode     []PodcastEpisode `json:"episode"`
+}
+
+// PodcastEpisode is one episode of a channel. Episodes are streamed by
+// StreamID, which is only set once the server has downloaded them.
+type PodcastEpisode struct {
+	ID          string `json:"id"`
+	StreamID    string `json:"streamId"`
+	ChannelID   string `json:"channelId"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	PublishDate string `json:"publishDate"` // ISO 8601
+	Status      string `json:"status"`      // "completed" once downloaded
+	Duration    int    `json:"duration"`    // seconds
+	CoverArt    string `json:"coverArt"`
+	Suffix      string `json:"suffix"`
+	BitRate     int    `json:"bitRate"`
+}
+
+type podcastsResponse struct {
+	Response struct {
+		baseResponse
+		Podcasts struct {
+			Channel []PodcastChannel `json:"channel"`
+		} `json:"podcasts"`
+	} `json:"subsonic-response"`
+}
+
+type newestPodcastsResponse struct {
+	Response struct {
+		baseResponse
+		NewestPodcasts struct {
+			Episode []PodcastEpisode `json:"episode"`
+		} `json:"newestPodcasts"`
+	} `json:"subsonic-response"`
+}
+
+// GetPodcasts returns the server's podcast channels, with their episodes
+// when includeEpisodes is set.
+func (c *Client) GetPodcasts(includeEpisodes bool) ([]PodcastChannel, error) {
+	params := url.Values{"includeEpisodes": {strconv.FormatBool(includeEpisodes)}}
+	var resp podcastsResponse
+	if err := c.get(string(CapPodcasts), params, &resp); err != nil {
+		return nil, fmt.Errorf("getPodcasts: %w", err)
+	}
+	if resp.Response.Status != "ok" {
+		return nil, apiErr(resp.Response.Error)
+	}
+	return resp.Response.Podcasts.Channel, nil
+}
+
+// GetNewestPodcasts returns the most recently published episodes across
+// every channel, newest first.
+func (c *Client) GetNewestPodcasts(count int) ([]PodcastEpisode, error) {
+	var resp newestPodcastsResponse
+	if err := c.get("getNewestPodcasts", url.Values{"count": {strconv.Itoa(count)}}, &resp); err != nil {
+		return nil, fmt.Errorf("getNewestPodcasts: %w", err)
+	}
+	if resp.Response.Status != "ok" {
+		return nil, apiErr(resp.Response.Error)
+	}
+	return resp.Response.NewestPodcasts.Episode, nil
+}
+
+// SyncPodcasts replaces the cached podcast channels and episodes with the
+// server's, keeping each episode's saved listening position. Channels and
+// episodes the server no longer lists are dropped.
+func SyncPodcasts(ctx context.Context, client *Client, db *sql.DB, logger *slog.Logger) (channels, episodes int, err error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	list, err := client.GetPodcasts(true)
+	if err != nil {
+		return 0, 0, fmt.Errorf("fetching podcasts: %w", err)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	channelStmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO podcast_channels (id, title, description, cover_art, status)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			title=excluded.title, description=excluded.description,
+			cover_art=excluded.cover_art, status=excluded.status
+	`)
+	if err != nil {
+		return 0, 0, fmt.Errorf("preparing channel stmt: %w", err)
+	}
+	defer channelStmt.Close()
+
+	episodeStmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO podcast_episodes (id, channel_id, stream_id, title, description, published,
+			status, duration_ms, format, bitrate, cover_art)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			channel_id=excluded.channel_id, stream_id=excluded.stream_id, title=excluded.title,
+			description=excluded.description, published=excluded.published, status=excluded.status,
+			duration_ms=excluded.duration_ms, format=excluded.format, bitrate=excluded.bitrate,
+			cover_art=excluded.cover_art
+	`)
+	if err != nil {
+		return 0, 0, fmt.Errorf("preparing episode stmt: %w", err)
+	}
+	defer episodeStmt.Close()
+
+	var channelIDs, episodeIDs []string
+	for _, ch := range list {
+		if _, err := channelStmt.ExecContext(ctx, ch.ID, ch.Title, ch.Description, ch.CoverArt, ch.Status); err != nil {
+			logger.Warn("podcast channel insert failed", "channel", ch.Title, "error", err)
+			continue
+		}
+		channelIDs = append(channelIDs, ch.ID)
+		channels++
+
+		for _, ep := range ch.Episode {
+			if _, err := episodeStmt.ExecContext(ctx, ep.ID, ch.ID, ep.StreamID, ep.Title, ep.Description,
+				ep.PublishDate, ep.Status, ep.Duration*1000, ep.Suffix, ep.BitRate, ep.CoverArt); err != nil {
+				logger.Warn("podcast episode insert failed", "episode", ep.Title, "error", err)
+				continue
+			}
+			episodeIDs = append(episodeIDs, ep.ID)
+			episodes++
+		}
+	}
+
+	// Drop what the server no longer has, passing the kept IDs as one JSON
+	// array rather than a parameter per ID.
+	for _, prune := range []struct {
+		table string
+		ids   []string
+	}{{"podcast_episodes", episodeIDs}, {"podcast_channels", channelIDs}} {
+		ids, err := json.Marshal(append([]string{}, prune.ids...))
+		if err != nil {
+			return 0, 0, err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+prune.table+` WHERE id NOT IN (SELECT value FROM json_each(?))`, string(ids)); err != nil {
+			return 0, 0, fmt.Errorf("pruning %s: %w", prune.table, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("committing podcasts: %w", err)
+	}
+	logger.Info("podcast sync complete", "channels", channels, "episodes", episodes)
+	return channels, episodes, nil
+}
@@ -12,12 +12,67 @@ import (
 type SyncResult struct {
 	Artists int
 	Albums  int
-	Tracks  int
-	Elapsed time.Duration
+	// Tracks counts every track in the synced albums, including those of
+	// unchanged albums, which were already stored.
+	Tracks int
+	// UpdatedTracks counts the tracks fetched and stored by this sync.
+	UpdatedTracks int
+	// UnchangedAlbums counts albums whose tracks were already cached and
+	// matched the server, so their getAlbum call was skipped.
+	UnchangedAlbums int
+	// NonAudio counts directory and video entries left out of the library.
+	NonAudio int
+	Elapsed  time.Duration
+}
+
+// cachedAlbum is the stored state of an album, compared against the server's
+// listing to decide whether its tracks need re-fetching.
+type cachedAlbum struct {
+	name       string
+	year       int
+	songCount  int
+	durationMs int
+	coverArt   string
+	tracks     int // tracks actually stored for the album
+	nonAudio   int // entries left out when its tracks were last fetched
+}
+
+// unchanged reports whether the server's album matches the cache exactly,
+// including having every track stored. The server's song count includes
+// non-audio entries, which aren't stored, so those are counted back in.
+func (c cachedAlbum) unchanged(alb Album) bool {
+	return c.name == alb.Name && c.year == alb.Year && c.songCount == alb.SongCount &&
+		c.durationMs == alb.Duration*1000 && c.coverArt == alb.CoverArt && c.tracks+c.nonAudio == alb.SongCount
+}
+
+// loadCachedAlbums reads every stored album along with its stored track count.
+func loadCachedAlbums(ctx context.Context, db *sql.DB) (map[string]cachedAlbum, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT a.id, a.name, a.year, a.song_count, a.duration_ms, a.cover_art, a.non_audio,
+			(SELECT COUNT(*) FROM tracks t WHERE t.album_id = a.id)
+		FROM albums a
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cached := make(map[string]cachedAlbum)
+	for rows.Next() {
+		var id string
+		var c cachedAlbum
+		if err := rows.Scan(&id, &c.name, &c.year, &c.songCount, &c.durationMs, &c.coverArt, &c.nonAudio, &c.tracks); err != nil {
+			return nil, err
+		}
+		cached[id] = c
+	}
+	return cached, rows.Err()
 }
 
 // Sync pulls the full library from a Subsonic server into the local SQLite cache.
 // It upserts all data, preserving kitsune-specific metadata (shuffle_exclude, linked_next_id).
+// Albums whose metadata and track count match the cache skip their getAlbum
+// call, so re-syncing an unchanged library costs one request per artist.
 func Sync(ctx context.Context, client *Client, db *sql.DB, logger *slog.Logger) (*SyncResult, error) {
 	if logger == nil {
 		logger = slog.Default()
@@ -31,6 +86,11 @@ func Sync(ctx context.Context, client *Client, db *sql.DB, logger *slog.Logger)
 		return nil, fmt.Errorf("fetching artists: %w", err)
 	}
 
+	cached, err := loadCachedAlbums(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("loading cached albums: %w", err)
+	}
+
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("starting transaction: %w", err)
@@ -49,12 +109,12 @@ func Sync(ctx context.Context, client *Client, db *sql.DB, logger *slog.Logger)
 	defer artistStmt.Close()
 
 	albumStmt, err := tx.PrepareContext(ctx, `
-		INSERT INTO albums (id, name, artist_id, artist_name, year, song_count, duration_ms, cover_art)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO albums (id, name, artist_id, artist_name, year, song_count, duration_ms, cover_art, created)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
 			name=excluded.name, artist_id=excluded.artist_id, artist_name=excluded.artist_name,
 			year=excluded.year, song_count=excluded.song_count, duration_ms=excluded.duration_ms,
-			cover_art=excluded.cover_art
+			cover_art=excluded.cover_art, created=excluded.created
 	`)
 	if err != nil {
 		return nil, fmt.Errorf("preparing album stmt: %w", err)
@@ -77,6 +137,12 @@ func Sync(ctx context.Context, client *Client, db *sql.DB, logger *slog.Logger)
 	}
 	defer trackStmt.Close()
 
+	nonAudioStmt, err := tx.PrepareContext(ctx, `UPDATE albums SET non_audio = ? WHERE id = ?`)
+	if err != nil {
+		return nil, fmt.Errorf("preparing non-audio stmt: %w", err)
+	}
+	defer nonAudioStmt.Close()
+
 	// Insert artists and fetch their albums + tracks.
 	for _, a := range artists {
 		if ctx.Err() != nil {
@@ -102,12 +168,18 @@ func Sync(ctx context.Context, client *Client, db *sql.DB, logger *slog.Logger)
 			}
 
 			if _, err := albumStmt.ExecContext(ctx, alb.ID, alb.Name, alb.ArtistID, alb.Artist,
-				alb.Year, alb.SongCount, alb.Duration*1000, alb.CoverArt); err != nil {
+				alb.Year, alb.SongCount, alb.Duration*1000, alb.CoverArt, alb.Created); err != nil {
 				logger.Warn("album insert failed", "album", alb.Name, "error", err)
 				continue
 			}
 			result.Albums++
 
+			if c, ok := cached[alb.ID]; ok && c.unchanged(alb) {
+				result.UnchangedAlbums++
+				result.Tracks += c.tracks
+				continue
+			}
+
 			// Fetch tracks for this album.
 			albumDetail, err := client.GetAlbum(alb.ID)
 			if err != nil {
@@ -115,7 +187,12 @@ func Sync(ctx context.Context, client *Client, db *sql.DB, logger *slog.Logger)
 				continue
 			}
 
+			nonAudio := 0
 			for _, s := range albumDetail.Song {
+				if !s.IsAudio() {
+					nonAudio++
+					continue
+				}
 				if _, err := trackStmt.ExecContext(ctx, s.ID, s.Title, s.Artist, s.Album,
 					s.AlbumID, s.ArtistID, s.TrackNum, s.DiscNum,
 					s.Duration*1000, s.Genre, s.Year, s.BitRate, s.Suffix, s.CoverArt); err != nil {
@@ -123,6 +200,11 @@ func Sync(ctx context.Context, client *Client, db *sql.DB, logger *slog.Logger)
 					continue
 				}
 				result.Tracks++
+				result.UpdatedTracks++
+			}
+			result.NonAudio += nonAudio
+			if _, err := nonAudioStmt.ExecContext(ctx, nonAudio, alb.ID); err != nil {
+				logger.Warn("album non-audio count failed", "album", alb.Name, "error", err)
 			}
 		}
 	}
@@ -136,6 +218,9 @@ func Sync(ctx context.Context, client *Client, db *sql.DB, logger *slog.Logger)
 		"artists", result.Artists,
 		"albums", result.Albums,
 		"tracks", result.Tracks,
+		"updated_tracks", result.UpdatedTracks,
+		"unchanged_albums", result.UnchangedAlbums,
+		"non_audio_skipped", result.NonAudio,
 		"elapsed", result.Elapsed.Round(time.Millisecond),
 	)
 
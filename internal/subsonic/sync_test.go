@@ -0,0 +1,102 @@
+package subsonic
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/simonhull/kitsune/internal/db"
+)
+
+// fakeLibrary serves one artist with the given albums over the Subsonic
+// API, counting getAlbum calls.
+func fakeLibrary(t *testing.T, albums map[string][]Song) (*Client, *atomic.Int32) {
+	t.Helper()
+	var getAlbums atomic.Int32
+	reply := func(w http.ResponseWriter, body map[string]any) {
+		body["status"] = "ok"
+		json.NewEncoder(w).Encode(map[string]any{"subsonic-response": body})
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/getArtists.view", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, map[string]any{"artists": map[string]any{"index": []any{
+			map[string]any{"name": "A", "artist": []Artist{{ID: "ar1", Name: "Artist", AlbumCount: len(albums)}}},
+		}}})
+	})
+	mux.HandleFunc("/rest/getArtist.view", func(w http.ResponseWriter, r *http.Request) {
+		detail := ArtistDetail{ID: "ar1", Name: "Artist"}
+		for id, songs := range albums {
+			detail.Album = append(detail.Album, Album{ID: id, Name: id, ArtistID: "ar1", SongCount: len(songs)})
+		}
+		reply(w, map[string]any{"artist": detail})
+	})
+	mux.HandleFunc("/rest/getAlbum.view", func(w http.ResponseWriter, r *http.Request) {
+		getAlbums.Add(1)
+		id := r.URL.Query().Get("id")
+		reply(w, map[string]any{"album": AlbumDetail{ID: id, Name: id, SongCount: len(albums[id]), Song: albums[id]}})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(srv.URL, "user", "pass", Options{})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client, &getAlbums
+}
+
+func TestSyncSkipsUnchangedAlbumsWithNonAudio(t *testing.T) {
+	song := func(id, albumID string) Song {
+		return Song{ID: id, Title: id, AlbumID: albumID, ArtistID: "ar1", Duration: 200, Suffix: "mp3"}
+	}
+	for _, tc := range []struct {
+		name  string
+		extra Song
+	}{
+		{"video", Song{ID: "v1", Title: "Music video", Duration: 240, Suffix: "mp4", IsVideo: true}},
+		{"directory", Song{ID: "d1", Title: "Scans", IsDir: true}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("XDG_DATA_HOME", t.TempDir())
+			logger := slog.New(slog.DiscardHandler)
+			database, err := db.Open(logger)
+			if err != nil {
+				t.Fatalf("opening database: %v", err)
+			}
+			defer database.Close()
+
+			client, getAlbums := fakeLibrary(t, map[string][]Song{
+				"al1": {song("s1", "al1"), song("s2", "al1"), tc.extra},
+				"al2": {song("s3", "al2")},
+			})
+
+			first, err := Sync(context.Background(), client, database.Conn, logger)
+			if err != nil {
+				t.Fatalf("first sync: %v", err)
+			}
+			if first.UpdatedTracks != 3 || first.NonAudio != 1 {
+				t.Fatalf("first sync stored %d tracks, skipped %d entries; want 3 and 1", first.UpdatedTracks, first.NonAudio)
+			}
+
+			getAlbums.Store(0)
+			second, err := Sync(context.Background(), client, database.Conn, logger)
+			if err != nil {
+				t.Fatalf("second sync: %v", err)
+			}
+			if n := getAlbums.Load(); n != 0 {
+				t.Errorf("second sync fetched %d albums, want none", n)
+			}
+			if second.UnchangedAlbums != 2 {
+				t.Errorf("second sync found %d unchanged albums, want 2", second.UnchangedAlbums)
+			}
+			if second.Tracks != 3 || second.UpdatedTracks != 0 {
+				t.Errorf("second sync counted %d tracks, %d updated; want 3 and 0", second.Tracks, second.UpdatedTracks)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"image"
+	"image/color"
 	"image/png"
 	"os"
 	"strings"
@@ -13,38 +14,96 @@ import (
 	_ "image/gif"
 	_ "image/jpeg"
 
+	"github.com/charmbracelet/lipgloss"
 	"golang.org/x/image/draw"
 )
 
 // imageCounter generates unique IDs for Kitty graphics placements.
 var imageCounter atomic.Uint32
 
-// AlbumArt handles terminal image rendering via the Kitty graphics protocol.
+// ArtBackend selects how album art is drawn.
+type ArtBackend string
+
+const (
+	ArtAuto   ArtBackend = "auto"   // detect from the environment
+	ArtKitty  ArtBackend = "kitty"  // Kitty graphics protocol
+	ArtITerm2 ArtBackend = "iterm2" // not yet implemented; falls back to blocks
+	ArtSixel  ArtBackend = "sixel"  // not yet implemented; falls back to blocks
+	ArtBlocks ArtBackend = "blocks" // half-block truecolor cells
+	ArtASCII  ArtBackend = "ascii"  // characters by brightness, for low-color terminals
+	ArtOff    ArtBackend = "off"    // no image work at all
+)
+
+// AlbumArt handles terminal image rendering via the Kitty graphics protocol,
+// falling back to half-block text art.
 type AlbumArt struct {
-	supported    bool
+	backend      ArtBackend
+	detected     ArtBackend
 	cache        map[string]uint32 // albumID → kitty image ID
 	imageData    map[string]string // albumID → base64 encoded PNG
 	cellSize     int               // art size in terminal cells (rows/cols)
 	currentImgID uint32            // ID of currently displayed image
+	blocks       map[string]string // albumID → rendered half-block art
+	rounded      bool              // Placeholder draws rounded corners
 }
 
 // NewAlbumArt creates an album art renderer.
 // cellSize is the number of terminal rows/columns for the art (square).
-func NewAlbumArt(cellSize int) *AlbumArt {
+// mode is the configured backend; "auto" or empty detects one, and any
+// explicit choice wins over detection.
+func NewAlbumArt(cellSize int, mode string) *AlbumArt {
 	if cellSize < 4 {
 		cellSize = 8
 	}
+	detected := ArtASCII
+	switch {
+	case detectKittyGraphics():
+		detected = ArtKitty
+	case detectTruecolor():
+		detected = ArtBlocks
+	}
+
+	backend := ArtBackend(strings.ToLower(mode))
+	switch backend {
+	case "", ArtAuto:
+		backend = detected
+	case ArtITerm2, ArtSixel:
+		backend = ArtBlocks
+	case ArtKitty, ArtBlocks, ArtASCII, ArtOff:
+	default:
+		backend = detected
+	}
+
 	return &AlbumArt{
-		supported: detectKittyGraphics(),
+		backend:   backend,
+		detected:  detected,
 		cache:     make(map[string]uint32),
 		imageData: make(map[string]string),
 		cellSize:  cellSize,
+		blocks:    make(map[string]string),
 	}
 }
 
-// Supported returns whether the terminal supports inline images.
+// Supported returns whether art is drawn with a graphics protocol.
 func (a *AlbumArt) Supported() bool {
-	return a.supported
+	return a.backend == ArtKitty
+}
+
+// TextArt returns whether art is drawn with characters (RenderText) rather
+// than a graphics protocol.
+func (a *AlbumArt) TextArt() bool {
+	return a.backend == ArtBlocks || a.backend == ArtASCII
+}
+
+// Backend returns the backend in use.
+func (a *AlbumArt) Backend() ArtBackend {
+	return a.backend
+}
+
+// Detected returns the backend environment detection picked, which may
+// differ from Backend when the config overrides it.
+func (a *AlbumArt) Detected() ArtBackend {
+	return a.detected
 }
 
 // CellSize returns the art dimensions in terminal cells.
@@ -55,7 +114,7 @@ func (a *AlbumArt) CellSize() int {
 // Upload transmits the image to the terminal (without displaying it) and returns
 // the Kitty image ID. Call Place() separately to position it.
 func (a *AlbumArt) Upload(albumID string, imageData []byte) string {
-	if !a.supported || len(imageData) == 0 {
+	if !a.Supported() || len(imageData) == 0 {
 		return ""
 	}
 
@@ -92,7 +151,7 @@ func (a *AlbumArt) Upload(albumID string, imageData []byte) string {
 // Place returns the escape sequence to display a previously uploaded image
 // at a specific position. Call this after the frame renders.
 func (a *AlbumArt) Place(albumID string) string {
-	if !a.supported {
+	if !a.Supported() {
 		return ""
 	}
 
@@ -132,7 +191,7 @@ func (a *AlbumArt) Place(albumID string) string {
 // This renders at the current cursor position. Text content should reserve
 // blank space where the image will appear.
 func (a *AlbumArt) RenderInline(albumID string, imageData []byte) string {
-	if !a.supported || len(imageData) == 0 {
+	if !a.Supported() || len(imageData) == 0 {
 		return ""
 	}
 
@@ -164,25 +223,115 @@ func (a *AlbumArt) Clear(albumID string) {
 	}
 	delete(a.cache, albumID)
 	delete(a.imageData, albumID)
+	delete(a.blocks, albumID)
 }
 
 // ClearAll removes all cached art.
 func (a *AlbumArt) ClearAll() {
 	// Delete all Kitty images.
-	if a.supported {
+	if a.Supported() {
 		fmt.Print("\x1b_Ga=d,d=a;\x1b\\")
 	}
 	a.cache = make(map[string]uint32)
 	a.imageData = make(map[string]string)
+	a.blocks = make(map[string]string)
 	a.currentImgID = 0
 }
 
+// RenderText renders the image with characters for the blocks or ascii
+// backend, rows cells tall and 2*rows wide (two vertical pixels per cell
+// makes a square image). The result is cached per album; returns "" if the
+// image can't be decoded or the backend draws graphics.
+func (a *AlbumArt) RenderText(albumID string, imageData []byte, rows int) string {
+	if art, ok := a.blocks[albumID]; ok {
+		return art
+	}
+	if !a.TextArt() || len(imageData) == 0 || rows <= 0 {
+		return ""
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(imageData))
+	if err != nil {
+		return ""
+	}
+	size := rows * 2
+	px := resizeImage(img, size, size)
+
+	var art string
+	if a.backend == ArtBlocks {
+		art = renderBlocks(px, rows)
+	} else {
+		art = renderASCII(px, rows)
+	}
+	a.blocks[albumID] = art
+	return art
+}
+
+// renderBlocks draws rows of "▀" half-block cells, each showing two vertical
+// pixels via its foreground and background colors. Works in any truecolor
+// terminal without a graphics protocol.
+func renderBlocks(px image.Image, rows int) string {
+	var sb strings.Builder
+	for y := 0; y < rows; y++ {
+		for x := 0; x < rows*2; x++ {
+			top := hexColor(px.At(x, y*2))
+			bottom := hexColor(px.At(x, y*2+1))
+			sb.WriteString(lipgloss.NewStyle().Foreground(top).Background(bottom).Render("▀"))
+		}
+		if y < rows-1 {
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
+
+// asciiRamp orders characters from darkest to brightest.
+const asciiRamp = " .:-=+*#%@"
+
+// renderASCII draws each cell as a character picked by the brightness of its
+// two pixels, with no color at all.
+func renderASCII(px image.Image, rows int) string {
+	var sb strings.Builder
+	for y := 0; y < rows; y++ {
+		for x := 0; x < rows*2; x++ {
+			lum := (luminance(px.At(x, y*2)) + luminance(px.At(x, y*2+1))) / 2
+			sb.WriteByte(asciiRamp[min(int(lum*float64(len(asciiRamp))), len(asciiRamp)-1)])
+		}
+		if y < rows-1 {
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
+
+// luminance returns a pixel's perceived brightness from 0 to 1.
+func luminance(c color.Color) float64 {
+	r, g, b, _ := c.RGBA()
+	return (0.2126*float64(r) + 0.7152*float64(g) + 0.0722*float64(b)) / 0xffff
+}
+
+// hexColor converts a pixel to a lipgloss truecolor value.
+func hexColor(c color.Color) lipgloss.Color {
+	r, g, b, _ := c.RGBA()
+	return lipgloss.Color(fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8))
+}
+
+// SetRounded sets whether Placeholder draws rounded corners, matching the
+// now playing art frame.
+func (a *AlbumArt) SetRounded(rounded bool) {
+	a.rounded = rounded
+}
+
 // Placeholder returns a text-based placeholder when art isn't available.
 func (a *AlbumArt) Placeholder() string {
 	size := a.cellSize
 	var lines []string
 
-	lines = append(lines, "┌"+strings.Repeat("─", size-2)+"┐")
+	tl, tr, bl, br := "┌", "┐", "└", "┘"
+	if a.rounded {
+		tl, tr, bl, br = "╭", "╮", "╰", "╯"
+	}
+	lines = append(lines, tl+strings.Repeat("─", size-2)+tr)
 	for i := 0; i < size-2; i++ {
 		if i == (size-2)/2 {
 			pad := (size - 4) / 2
@@ -191,7 +340,7 @@ func (a *AlbumArt) Placeholder() string {
 			lines = append(lines, "│"+strings.Repeat(" ", size-2)+"│")
 		}
 	}
-	lines = append(lines, "└"+strings.Repeat("─", size-2)+"┘")
+	lines = append(lines, bl+strings.Repeat("─", size-2)+br)
 
 	return strings.Join(lines, "\n")
 }
@@ -276,6 +425,12 @@ func detectKittyGraphics() bool {
 	return false
 }
 
+// detectTruecolor reports whether the terminal advertises 24-bit color.
+func detectTruecolor() bool {
+	ct := strings.ToLower(os.Getenv("COLORTERM"))
+	return ct == "truecolor" || ct == "24bit"
+}
+
 // --- Image resizing ---
 
 func resizeImage(src image.Image, width, height int) image.Image {
@@ -3,9 +3,13 @@ package ui
 import (
 	"strings"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/simonhull/kitsune/internal/db"
 )
 
+// marqueeGap separates the end of a scrolling name from its restart.
+const marqueeGap = "   "
+
 // ArtistNav is the slim left panel showing just artist names.
 type ArtistNav struct {
 	styles  *Styles
@@ -17,6 +21,9 @@ type ArtistNav struct {
 	focused bool
 	// selectedID is the currently filtered artist (empty = no filter).
 	selectedID string
+	// marquee is how many cells the cursor row's name has scrolled, when
+	// it's too long to fit.
+	marquee int
 }
 
 // ArtistRow is a minimal artist entry for the nav panel.
@@ -43,6 +50,15 @@ func (n *ArtistNav) SetSize(w, h int) { n.width = w; n.height = h }
 func (n *ArtistNav) SetFocused(f bool) { n.focused = f }
 func (n *ArtistNav) SelectedID() string { return n.selectedID }
 func (n *ArtistNav) Offset() int        { return n.offset }
+func (n *ArtistNav) Cursor() int        { return n.cursor }
+
+// CursorID returns the ID of the artist under the cursor.
+func (n *ArtistNav) CursorID() string {
+	if n.cursor >= 0 && n.cursor < len(n.artists) {
+		return n.artists[n.cursor].ID
+	}
+	return ""
+}
 
 // Select confirms the current cursor as the filter.
 func (n *ArtistNav) Select() string {
@@ -70,6 +86,18 @@ func (n *ArtistNav) SelectByID(artistID string) {
 	}
 }
 
+// MoveToID puts the cursor on the given artist, if listed, without
+// filtering by it.
+func (n *ArtistNav) MoveToID(artistID string) {
+	for i, a := range n.artists {
+		if a.ID == artistID {
+			n.cursor = i
+			n.scrollIntoView()
+			return
+		}
+	}
+}
+
 // SetCursor sets cursor to a specific row.
 func (n *ArtistNav) SetCursor(idx int) {
 	if idx < 0 {
@@ -111,6 +139,22 @@ func (n *ArtistNav) MoveBottom() {
 	}
 }
 
+// MoveScreenTop and MoveScreenMiddle put the cursor on the first or middle
+// row on screen, like vim's H and M.
+func (n *ArtistNav) MoveScreenTop()    { n.moveOnScreen(0) }
+func (n *ArtistNav) MoveScreenMiddle() { n.moveOnScreen(1) }
+
+// moveOnScreen moves to the top (0) or middle (1) of the rows between
+// offset and offset+height.
+func (n *ArtistNav) moveOnScreen(pos int) {
+	rows := min(n.height, len(n.artists)-n.offset)
+	if rows <= 0 {
+		return
+	}
+	n.cursor = n.offset + (rows-1)*pos/2
+	n.scrollIntoView()
+}
+
 func (n *ArtistNav) HalfPageDown() {
 	n.cursor += n.height / 2
 	if n.cursor >= len(n.artists) {
@@ -127,6 +171,24 @@ func (n *ArtistNav) HalfPageUp() {
 	n.scrollIntoView()
 }
 
+// NeedsMarquee reports whether the focused cursor row's name is too long for
+// the panel and should scroll.
+func (n *ArtistNav) NeedsMarquee() bool {
+	if !n.focused || n.cursor < 0 || n.cursor >= len(n.artists) {
+		return false
+	}
+	return lipgloss.Width(n.artists[n.cursor].Name) > n.width-2
+}
+
+// AdvanceMarquee scrolls the cursor row's name one cell.
+func (n *ArtistNav) AdvanceMarquee() {
+	if !n.NeedsMarquee() {
+		n.marquee = 0
+		return
+	}
+	n.marquee = (n.marquee + 1) % lipgloss.Width(n.artists[n.cursor].Name+marqueeGap)
+}
+
 func (n *ArtistNav) View() string {
 	if len(n.artists) == 0 {
 		return n.styles.Dim.Render("no artists")
@@ -140,14 +202,17 @@ func (n *ArtistNav) View() string {
 
 	for i := n.offset; i < end; i++ {
 		a := n.artists[i]
+		isCursor := i == n.cursor && n.focused
+
 		name := a.Name
 		availWidth := n.width - 2 // 1 padding each side
-		if len(name) > availWidth {
-			name = name[:availWidth-1] + "…"
+		if isCursor && lipgloss.Width(name) > availWidth {
+			name = marqueeWindow(name, availWidth, n.marquee)
+		} else {
+			name = truncateRunes(name, max(availWidth, 1))
 		}
 		line := " " + name
 
-		isCursor := i == n.cursor && n.focused
 		isSelected := a.ID == n.selectedID
 
 		switch {
@@ -167,7 +232,33 @@ func (n *ArtistNav) View() string {
 	return b.String()
 }
 
+// marqueeWindow returns width cells of s looped around, starting offset
+// cells in, without splitting wide characters.
+func marqueeWindow(s string, width, offset int) string {
+	loop := []rune(s + marqueeGap + s)
+	i, skipped := 0, 0
+	for i < len(loop) && skipped < offset {
+		skipped += lipgloss.Width(string(loop[i]))
+		i++
+	}
+
+	var b strings.Builder
+	cells := 0
+	for ; i < len(loop); i++ {
+		w := lipgloss.Width(string(loop[i]))
+		if cells+w > width {
+			break
+		}
+		b.WriteRune(loop[i])
+		cells += w
+	}
+	return b.String() + strings.Repeat(" ", max(width-cells, 0))
+}
+
+// scrollIntoView keeps the cursor on screen. Every cursor move passes through
+// here, so it also restarts the marquee.
 func (n *ArtistNav) scrollIntoView() {
+	n.marquee = 0
 	if n.height <= 0 {
 		return
 	}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Backdrop paints one background color under a whole rendered view, so the
+// app sits on its own surface rather than the terminal's.
+type Backdrop struct {
+	on string // SGR sequence setting the background
+}
+
+// NewBackdrop returns a backdrop of color c, or nil when there's no color or
+// the terminal has no colors to paint with.
+func NewBackdrop(c lipgloss.Color) *Backdrop {
+	profile := lipgloss.ColorProfile()
+	if c == "" || profile == termenv.Ascii {
+		return nil
+	}
+	seq := profile.Color(string(c))
+	if seq == nil {
+		return nil
+	}
+	return &Backdrop{on: termenv.CSI + seq.Sequence(true) + "m"}
+}
+
+// Paint lays the backdrop under view, filling it out to width × height.
+// Every cell is written as a space rather than left to the terminal's
+// erase-to-end-of-line, which some terminals fill with their own
+// background, and the color is set again after each reset the view's
+// styles emit. A nil Backdrop returns view unchanged.
+func (b *Backdrop) Paint(view string, width, height int) string {
+	if b == nil {
+		return view
+	}
+	lines := strings.Split(view, "\n")
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	reapply := strings.NewReplacer(
+		"\x1b[0m", "\x1b[0m"+b.on,
+		"\x1b[m", "\x1b[m"+b.on,
+	)
+	var sb strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(b.on)
+		sb.WriteString(reapply.Replace(line))
+		if pad := width - lipgloss.Width(line); pad > 0 {
+			sb.WriteString(strings.Repeat(" ", pad))
+		}
+		sb.WriteString("\x1b[0m")
+	}
+	return sb.String()
+}
@@ -14,6 +14,9 @@ const (
 	ContentArtist ContentRowKind = iota
 	ContentAlbum
 	ContentTrack
+	// ContentSeparator is a blank spacer before an artist header; the
+	// cursor never lands on it.
+	ContentSeparator
 )
 
 // ContentRow is a single row in the content browser's flat list.
@@ -30,9 +33,11 @@ type ContentRow struct {
 	TrackTitle string
 	DurationMs int
 	Format     string
+	Played     bool // played through at least once
 }
 
-// ContentBrowser shows tracks grouped by Artist → Album, all expanded.
+// ContentBrowser shows tracks grouped by Artist → Album, all expanded, or a
+// flat list of every album.
 type ContentBrowser struct {
 	styles   *Styles
 	database *db.DB
@@ -47,17 +52,32 @@ type ContentBrowser struct {
 	focused bool
 	// Current artist filter (empty = show all).
 	filterArtistID string
+	// lastFilterArtistID is the filter ClearFilter removed, for ToggleFilter.
+	lastFilterArtistID string
+	// separators inserts a spacer row between artists in the unfiltered view.
+	separators bool
+	// links makes artist and album names hyperlinks when set.
+	links Linker
+	// albumSort is set while the browser shows albumRows, a flat list of
+	// every album, instead of the artist tree.
+	albumSort db.AlbumSort
+	albumRows []ContentRow
+	// unplayedOnly hides played tracks, and albums and artists with
+	// nothing left unplayed.
+	unplayedOnly bool
 }
 
-// NewContentBrowser creates and eagerly loads the content browser.
-func NewContentBrowser(database *db.DB, styles *Styles) *ContentBrowser {
+// NewContentBrowser creates and eagerly loads the content browser. With
+// separators set, the unfiltered view leaves a blank row between artists.
+func NewContentBrowser(database *db.DB, styles *Styles, separators bool) *ContentBrowser {
 	cb := &ContentBrowser{
-		styles:   styles,
-		database: database,
-		focused:  true,
+		styles:     styles,
+		database:   database,
+		focused:    true,
+		separators: separators,
 	}
 	cb.loadAll()
-	cb.visible = cb.allRows
+	cb.rebuildVisible()
 	return cb
 }
 
@@ -81,11 +101,12 @@ func (cb *ContentBrowser) loadAll() {
 
 		for _, album := range albums {
 			cb.allRows = append(cb.allRows, ContentRow{
-				Kind:      ContentAlbum,
-				ArtistID:  artist.ID,
-				AlbumID:   album.ID,
-				AlbumName: album.Name,
-				AlbumYear: album.Year,
+				Kind:       ContentAlbum,
+				ArtistID:   artist.ID,
+				AlbumID:    album.ID,
+				ArtistName: artist.Name,
+				AlbumName:  album.Name,
+				AlbumYear:  album.Year,
 			})
 
 			tracks, err := cb.database.TracksForAlbum(album.ID)
@@ -106,6 +127,7 @@ func (cb *ContentBrowser) loadAll() {
 					TrackTitle: t.Title,
 					DurationMs: t.DurationMs,
 					Format:     t.Format,
+					Played:     t.PlayCount > 0,
 				})
 			}
 		}
@@ -115,9 +137,75 @@ func (cb *ContentBrowser) loadAll() {
 func (cb *ContentBrowser) SetSize(w, h int) { cb.width = w; cb.height = h }
 func (cb *ContentBrowser) SetFocused(f bool) { cb.focused = f }
 func (cb *ContentBrowser) Offset() int       { return cb.offset }
+func (cb *ContentBrowser) Cursor() int       { return cb.cursor }
+
+// SetLinker makes artist and album names link to their web pages.
+func (cb *ContentBrowser) SetLinker(l Linker) { cb.links = l }
+
+// ShowAlbums lists every album, flat, in the given order.
+func (cb *ContentBrowser) ShowAlbums(sort db.AlbumSort) error {
+	albums, err := cb.database.AllAlbums(sort)
+	if err != nil {
+		return err
+	}
+	rows := make([]ContentRow, len(albums))
+	for i, a := range albums {
+		rows[i] = ContentRow{
+			Kind:       ContentAlbum,
+			ArtistID:   a.ArtistID,
+			AlbumID:    a.ID,
+			ArtistName: a.ArtistName,
+			AlbumName:  a.Name,
+			AlbumYear:  a.Year,
+		}
+	}
+	cb.albumSort, cb.albumRows = sort, rows
+	cb.rebuildVisible()
+	cb.cursor = 0
+	cb.offset = 0
+	return nil
+}
 
-// FilterByArtist shows only the given artist's content.
+// ShowTree goes back from the albums list to the artist tree.
+func (cb *ContentBrowser) ShowTree() {
+	cb.albumSort, cb.albumRows = "", nil
+	cb.rebuildVisible()
+	cb.cursor = 0
+	cb.offset = 0
+}
+
+// AlbumSort returns the albums list's order, or "" while showing the tree.
+func (cb *ContentBrowser) AlbumSort() db.AlbumSort {
+	return cb.albumSort
+}
+
+// ToggleUnplayed switches between every track and only unplayed ones,
+// returning whether only unplayed tracks now show.
+func (cb *ContentBrowser) ToggleUnplayed() bool {
+	cb.unplayedOnly = !cb.unplayedOnly
+	cb.rebuildVisible()
+	cb.cursor = min(cb.cursor, max(len(cb.visible)-1, 0))
+	cb.skipSeparator(-1)
+	cb.scrollIntoView()
+	return cb.unplayedOnly
+}
+
+// MarkPlayed marks a track as played. It stays listed until the view is
+// rebuilt, so the unplayed list doesn't shift under the cursor.
+func (cb *ContentBrowser) MarkPlayed(trackID string) {
+	for _, rows := range [][]ContentRow{cb.allRows, cb.visible} {
+		for i := range rows {
+			if rows[i].Kind == ContentTrack && rows[i].TrackID == trackID {
+				rows[i].Played = true
+			}
+		}
+	}
+}
+
+// FilterByArtist shows only the given artist's content, leaving the albums
+// list for the tree.
 func (cb *ContentBrowser) FilterByArtist(artistID string) {
+	cb.albumSort, cb.albumRows = "", nil
 	cb.filterArtistID = artistID
 	cb.rebuildVisible()
 	cb.cursor = 0
@@ -126,12 +214,35 @@ func (cb *ContentBrowser) FilterByArtist(artistID string) {
 
 // ClearFilter shows all content.
 func (cb *ContentBrowser) ClearFilter() {
+	if cb.filterArtistID != "" {
+		cb.lastFilterArtistID = cb.filterArtistID
+	}
 	cb.filterArtistID = ""
-	cb.visible = cb.allRows
+	cb.rebuildVisible()
 	cb.cursor = 0
 	cb.offset = 0
 }
 
+// FilterArtistID returns the artist the browser is filtered to, or "".
+func (cb *ContentBrowser) FilterArtistID() string {
+	return cb.filterArtistID
+}
+
+// ToggleFilter switches between the artist filter and the full library,
+// restoring the last filtered artist. Showing everything keeps the cursor on
+// that artist. Returns the artist now filtered ("" for all).
+func (cb *ContentBrowser) ToggleFilter() string {
+	if artistID := cb.filterArtistID; artistID != "" {
+		cb.ClearFilter()
+		cb.ScrollToArtist(artistID)
+		return ""
+	}
+	if cb.lastFilterArtistID != "" {
+		cb.FilterByArtist(cb.lastFilterArtistID)
+	}
+	return cb.filterArtistID
+}
+
 // ScrollToArtist scrolls to the given artist's header row.
 func (cb *ContentBrowser) ScrollToArtist(artistID string) {
 	for i, row := range cb.visible {
@@ -185,6 +296,7 @@ func (cb *ContentBrowser) SetCursor(idx int) {
 		idx = 0
 	}
 	cb.cursor = idx
+	cb.skipSeparator(1)
 	cb.scrollIntoView()
 }
 
@@ -193,6 +305,7 @@ func (cb *ContentBrowser) SetCursor(idx int) {
 func (cb *ContentBrowser) MoveUp() {
 	if cb.cursor > 0 {
 		cb.cursor--
+		cb.skipSeparator(-1)
 		cb.scrollIntoView()
 	}
 }
@@ -200,6 +313,7 @@ func (cb *ContentBrowser) MoveUp() {
 func (cb *ContentBrowser) MoveDown() {
 	if cb.cursor < len(cb.visible)-1 {
 		cb.cursor++
+		cb.skipSeparator(1)
 		cb.scrollIntoView()
 	}
 }
@@ -216,11 +330,29 @@ func (cb *ContentBrowser) MoveBottom() {
 	}
 }
 
+// MoveScreenTop and MoveScreenMiddle put the cursor on the first or middle
+// row on screen, like vim's H and M.
+func (cb *ContentBrowser) MoveScreenTop()    { cb.moveOnScreen(0) }
+func (cb *ContentBrowser) MoveScreenMiddle() { cb.moveOnScreen(1) }
+
+// moveOnScreen moves to the top (0) or middle (1) of the rows between
+// offset and offset+height, stepping off separators below.
+func (cb *ContentBrowser) moveOnScreen(pos int) {
+	rows := min(cb.height, len(cb.visible)-cb.offset)
+	if rows <= 0 {
+		return
+	}
+	cb.cursor = cb.offset + (rows-1)*pos/2
+	cb.skipSeparator(1)
+	cb.scrollIntoView()
+}
+
 func (cb *ContentBrowser) HalfPageDown() {
 	cb.cursor += cb.height / 2
 	if cb.cursor >= len(cb.visible) {
 		cb.cursor = len(cb.visible) - 1
 	}
+	cb.skipSeparator(1)
 	cb.scrollIntoView()
 }
 
@@ -229,6 +361,7 @@ func (cb *ContentBrowser) HalfPageUp() {
 	if cb.cursor < 0 {
 		cb.cursor = 0
 	}
+	cb.skipSeparator(-1)
 	cb.scrollIntoView()
 }
 
@@ -261,15 +394,25 @@ func (cb *ContentBrowser) renderRow(row ContentRow, selected bool) string {
 	var line string
 
 	switch row.Kind {
+	case ContentSeparator:
+		return ""
+
 	case ContentArtist:
-		line = fmt.Sprintf("  %s", row.ArtistName)
+		line = fmt.Sprintf("  %s", cb.links.wrap("artist", row.ArtistID, row.ArtistName, row.ArtistName))
 
 	case ContentAlbum:
 		yearStr := ""
 		if row.AlbumYear > 0 {
 			yearStr = cb.styles.Dim.Render(fmt.Sprintf(" %d", row.AlbumYear))
 		}
-		line = fmt.Sprintf("    %s%s", row.AlbumName, yearStr)
+		album := cb.links.wrap("album", row.AlbumID, row.AlbumName, row.AlbumName)
+		if cb.albumSort != "" {
+			// The flat list has no artist headers, so name the artist here.
+			artist := cb.styles.Dim.Render(" — " + row.ArtistName)
+			line = fmt.Sprintf("  %s%s%s", album, artist, yearStr)
+			break
+		}
+		line = fmt.Sprintf("    %s%s", album, yearStr)
 
 	case ContentTrack:
 		dur := formatDuration(row.DurationMs)
@@ -283,7 +426,12 @@ func (cb *ContentBrowser) renderRow(row ContentRow, selected bool) string {
 		if len(title) > titleWidth {
 			title = title[:titleWidth-1] + "…"
 		}
-		line = fmt.Sprintf("      %s  %-*s %s", num, titleWidth, title, cb.styles.Dim.Render(dur))
+		title = fmt.Sprintf("%-*s", titleWidth, title)
+		mark := " "
+		if row.Played {
+			mark, title = cb.styles.Dim.Render("✓"), cb.styles.Dim.Render(title)
+		}
+		line = fmt.Sprintf("    %s %s  %s %s", mark, num, title, cb.styles.Dim.Render(dur))
 	}
 
 	if selected && cb.focused {
@@ -295,16 +443,74 @@ func (cb *ContentBrowser) renderRow(row ContentRow, selected bool) string {
 // --- Internal ---
 
 func (cb *ContentBrowser) rebuildVisible() {
-	if cb.filterArtistID == "" {
+	var unplayed map[string]bool // artist and album IDs with unplayed tracks
+	if cb.unplayedOnly {
+		unplayed = make(map[string]bool)
+		for _, row := range cb.allRows {
+			if row.Kind == ContentTrack && !row.Played {
+				unplayed[row.ArtistID], unplayed[row.AlbumID] = true, true
+			}
+		}
+	}
+	if cb.albumSort != "" {
+		cb.visible = cb.albumRows
+		if unplayed != nil {
+			cb.visible = nil
+			for _, row := range cb.albumRows {
+				if unplayed[row.AlbumID] {
+					cb.visible = append(cb.visible, row)
+				}
+			}
+		}
+		return
+	}
+	if cb.filterArtistID == "" && !cb.separators && unplayed == nil {
 		cb.visible = cb.allRows
 		return
 	}
 
-	cb.visible = cb.visible[:0]
+	// Build a fresh slice: visible may alias allRows.
+	var visible []ContentRow
 	for _, row := range cb.allRows {
-		if row.ArtistID == cb.filterArtistID {
-			cb.visible = append(cb.visible, row)
+		if cb.filterArtistID != "" && row.ArtistID != cb.filterArtistID {
+			continue
+		}
+		if unplayed != nil {
+			switch row.Kind {
+			case ContentArtist:
+				if !unplayed[row.ArtistID] {
+					continue
+				}
+			case ContentAlbum:
+				if !unplayed[row.AlbumID] {
+					continue
+				}
+			case ContentTrack:
+				if row.Played {
+					continue
+				}
+			}
+		}
+		if cb.filterArtistID == "" && row.Kind == ContentArtist && len(visible) > 0 {
+			visible = append(visible, ContentRow{Kind: ContentSeparator, ArtistID: row.ArtistID})
+		}
+		visible = append(visible, row)
+	}
+	cb.visible = visible
+}
+
+// skipSeparator steps the cursor off a separator row in direction dir
+// (1 or -1), reversing at either end of the list.
+func (cb *ContentBrowser) skipSeparator(dir int) {
+	for range 2 {
+		for cb.cursor >= 0 && cb.cursor < len(cb.visible) && cb.visible[cb.cursor].Kind == ContentSeparator {
+			cb.cursor += dir
+		}
+		if cb.cursor >= 0 && cb.cursor < len(cb.visible) {
+			return
 		}
+		cb.cursor -= dir
+		dir = -dir
 	}
 }
 
@@ -334,6 +540,7 @@ func (cb *ContentBrowser) AllVisibleTracks() []db.TrackRow {
 			Artist:     row.ArtistName,
 			Album:      row.AlbumName,
 			AlbumID:    row.AlbumID,
+			ArtistID:   row.ArtistID,
 			TrackNum:   row.TrackNum,
 			DurationMs: row.DurationMs,
 			Year:       row.AlbumYear,
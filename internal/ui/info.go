@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// LibraryStats holds the figures shown on the info screen.
+type LibraryStats struct {
+	Artists       int
+	Albums        int
+	Tracks        int
+	TotalDuration time.Duration
+	DBSize        int64
+	Listened      time.Duration // playback this session
+	ListenedTotal time.Duration // playback across all sessions
+	LastSync      time.Time
+	ServerURL     string
+	Server        string // server type and version, e.g. "navidrome 0.53.3 (API 1.16.1)"
+	Version       string
+}
+
+// Info is the library info / about overlay.
+type Info struct {
+	styles *Styles
+	open   bool
+	stats  LibraryStats
+	width  int
+	height int
+	clock  string // time.Format layout for times of day
+}
+
+// NewInfo creates an info overlay.
+func NewInfo(styles *Styles) *Info {
+	return &Info{styles: styles, clock: ClockLayout("")}
+}
+
+// ClockLayout returns the time.Format layout for times of day in the named
+// clock: "12h" gives "3:04 PM", anything else 24 hour "15:04".
+func ClockLayout(clock string) string {
+	if clock == "12h" {
+		return "3:04 PM"
+	}
+	return "15:04"
+}
+
+// SetClock sets the layout times of day are shown in, from ClockLayout.
+func (i *Info) SetClock(layout string) {
+	i.clock = layout
+}
+
+// IsOpen returns whether the overlay is visible.
+func (i *Info) IsOpen() bool {
+	return i.open
+}
+
+// Open shows the overlay with the given stats.
+func (i *Info) Open(stats LibraryStats) {
+	i.open = true
+	i.stats = stats
+}
+
+// Close hides the overlay.
+func (i *Info) Close() {
+	i.open = false
+}
+
+// SetSize updates the available dimensions for the overlay.
+func (i *Info) SetSize(width, height int) {
+	i.width = width
+	i.height = height
+}
+
+// View renders the overlay as a centered panel in the content area.
+func (i *Info) View() string {
+	if !i.open {
+		return ""
+	}
+
+	s := i.stats
+	lastSync := "never"
+	if !s.LastSync.IsZero() {
+		lastSync = s.LastSync.Format("2006-01-02 " + i.clock)
+	}
+	server := s.ServerURL
+	if server == "" {
+		server = "not configured"
+	}
+
+	fields := [][2]string{
+		{"Artists", fmt.Sprintf("%d", s.Artists)},
+		{"Albums", fmt.Sprintf("%d", s.Albums)},
+		{"Tracks", fmt.Sprintf("%d", s.Tracks)},
+		{"Runtime", formatLongDuration(s.TotalDuration)},
+		{"Listened", formatLongDuration(s.Listened) + " this session, " + formatLongDuration(s.ListenedTotal) + " total"},
+		{"Database", formatBytes(s.DBSize)},
+		{"Last sync", lastSync},
+		{"Server", server},
+	}
+	if s.Server != "" {
+		fields = append(fields, [2]string{"", s.Server})
+	}
+	fields = append(fields, [2]string{"Version", "kitsune " + s.Version})
+
+	rows := []string{i.styles.QueueHeader.Padding(0).Render("Library info"), ""}
+	for _, f := range fields {
+		label := i.styles.Dim.Render(fmt.Sprintf("%-10s", f[0]))
+		rows = append(rows, label+" "+f[1])
+	}
+	rows = append(rows, "", i.styles.Dim.Render("press any key to close"))
+
+	box := paletteBoxStyle(i.styles).
+		Padding(1, 2).
+		Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+
+	return lipgloss.Place(i.width, i.height,
+		lipgloss.Center, lipgloss.Center,
+		box,
+		lipgloss.WithWhitespaceChars(" "))
+}
+
+// formatLongDuration renders a library-scale duration like "3d 4h 12m".
+func formatLongDuration(d time.Duration) string {
+	totalMin := int(d.Minutes())
+	days := totalMin / (24 * 60)
+	hours := totalMin / 60 % 24
+	mins := totalMin % 60
+
+	var parts []string
+	if days > 0 {
+		parts = append(parts, fmt.Sprintf("%dd", days))
+	}
+	if days > 0 || hours > 0 {
+		parts = append(parts, fmt.Sprintf("%dh", hours))
+	}
+	parts = append(parts, fmt.Sprintf("%dm", mins))
+	return strings.Join(parts, " ")
+}
+
+// formatBytes renders a byte count with a binary unit suffix.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -424,8 +424,5 @@ func (l *Library) moveToCursorParent() {
 }
 
 func formatDuration(ms int) string {
-	totalSec := ms / 1000
-	m := totalSec / 60
-	s := totalSec % 60
-	return fmt.Sprintf("%d:%02d", m, s)
+	return formatTimestamp(ms / 1000)
 }
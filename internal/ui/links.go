@@ -0,0 +1,18 @@
+package ui
+
+// Linker returns the web page for an artist or album, or "" for none. kind
+// is "artist" or "album", as in palette results.
+type Linker func(kind, id, name string) string
+
+// wrap makes text an OSC 8 hyperlink to the item's page, for terminals that
+// support them; others show the text as is.
+func (l Linker) wrap(kind, id, name, text string) string {
+	if l == nil || id == "" {
+		return text
+	}
+	url := l(kind, id, name)
+	if url == "" {
+		return text
+	}
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"math"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -12,23 +13,98 @@ type NowPlayingInfo struct {
 	Title      string
 	Artist     string
 	Album      string
+	AlbumID    string
+	ArtistID   string
 	Year       int
+	Format     string // source file format, e.g. "flac"
+	BitRate    int    // source bitrate in kbps, 0 if unknown
+	Transcode  string // target format when the server transcodes, else ""
 	ElapsedSec float64
 	DurationMs int
 	Paused     bool
-	HasArt     bool
+	Halted     bool      // stopped by the user; the track stays current
+	Remaining  bool      // show time left instead of the total
+	HasArt     bool      // reserve space for graphics-protocol art
+	Art        string    // pre-rendered text art (half-blocks or ASCII), drawn left of the text
+	Marks      []float64 // bookmark positions in seconds, ticked on the seek bar
+	NoSeek     bool      // the stream can't seek; noted beside the quality
+	Levels     []float64 // band levels, low to high, drawn as bars under the seek bar
+	// Stopped means nothing is playing but the queue still holds Queued tracks.
+	Stopped bool
+	Queued  int
 }
 
+// ArtPosition places the album art in the now playing panel.
+type ArtPosition string
+
+const (
+	ArtLeft   ArtPosition = "left"
+	ArtRight  ArtPosition = "right"
+	ArtCenter ArtPosition = "center" // no art; the track info centered
+)
+
 // NowPlayingPanel renders the now playing section with seek bar.
 type NowPlayingPanel struct {
-	styles  *Styles
-	width   int
-	artCols int
+	styles    *Styles
+	width     int
+	artCols   int
+	artBorder bool
+	artPos    ArtPosition
+	dense     bool
+	// elapsedOnly drops the total (or remaining) time after the seek bar.
+	elapsedOnly bool
+	bar         ProgressBar
+	gradient    *Gradient // shades the played cells while playing, if set
+	links       Linker
+	// rightInset is how many columns the art took on the right in the last
+	// render, and seekRow the row the seek bar was on (-1 for none), so
+	// clicks can be mapped onto the seek bar.
+	rightInset int
+	seekRow    int
 }
 
 // NewNowPlayingPanel creates a new now playing panel.
 func NewNowPlayingPanel(styles *Styles) *NowPlayingPanel {
-	return &NowPlayingPanel{styles: styles}
+	return &NowPlayingPanel{styles: styles, artPos: ArtLeft, bar: progressBars["line"], seekRow: -1}
+}
+
+// SetLinker makes the artist and album line link to the album's web page.
+func (n *NowPlayingPanel) SetLinker(l Linker) {
+	n.links = l
+}
+
+// SetGradient shades the played part of the seek bar with g; nil draws it
+// in one color.
+func (n *NowPlayingPanel) SetGradient(g *Gradient) {
+	n.gradient = g
+}
+
+// SetProgressBar sets the glyphs the seek bar is drawn with.
+func (n *NowPlayingPanel) SetProgressBar(bar ProgressBar) {
+	n.bar = bar
+}
+
+// SetArtPosition puts the art on the left or right of the track info, or
+// drops it and centers the info. Unknown positions mean left.
+func (n *NowPlayingPanel) SetArtPosition(pos ArtPosition) {
+	switch pos {
+	case ArtRight, ArtCenter:
+		n.artPos = pos
+	default:
+		n.artPos = ArtLeft
+	}
+}
+
+// RightInset returns how many columns the art took on the right edge in the
+// last render (0 unless the art is on the right).
+func (n *NowPlayingPanel) RightInset() int {
+	return n.rightInset
+}
+
+// SeekRow returns the row of the last render the seek bar was on, counted
+// from the top of the panel, or -1 if it had none.
+func (n *NowPlayingPanel) SeekRow() int {
+	return n.seekRow
 }
 
 // SetWidth updates the panel width.
@@ -36,25 +112,66 @@ func (n *NowPlayingPanel) SetWidth(width int) {
 	n.width = width
 }
 
-// SetArtCols sets how many columns to reserve for album art on the left.
+// SetArtCols sets how many columns to reserve for graphics album art.
 func (n *NowPlayingPanel) SetArtCols(cols int) {
 	n.artCols = cols
 }
 
+// SetArtBorder sets whether the art region gets a themed frame. A framed
+// region is always reserved, with a placeholder until art arrives, so the
+// text beside it never shifts.
+func (n *NowPlayingPanel) SetArtBorder(border bool) {
+	n.artBorder = border
+}
+
+// SetDense switches to a two-line layout without art: title and artist on
+// one line, the seek bar below.
+func (n *NowPlayingPanel) SetDense(dense bool) {
+	n.dense = dense
+}
+
+// SetElapsedOnly shows just the time played beside the seek bar.
+func (n *NowPlayingPanel) SetElapsedOnly(on bool) {
+	n.elapsedOnly = on
+}
+
 // Height returns how many rows the now playing section needs.
 func (n *NowPlayingPanel) Height() int {
+	if n.dense {
+		return 4
+	}
+	if n.artBorder && n.artPos != ArtCenter {
+		return 7 // the frame adds a row above and below the art
+	}
 	return 5
 }
 
+// ArtRows returns how many rows of text art fit beside the track info.
+func (n *NowPlayingPanel) ArtRows() int {
+	return 3
+}
+
 // View renders the now playing section.
 func (n *NowPlayingPanel) View(info NowPlayingInfo) string {
+	n.rightInset, n.seekRow = 0, -1
 	if n.width < 20 {
 		return ""
 	}
 
+	if n.artPos == ArtCenter || n.dense {
+		info.Art, info.HasArt = "", false
+	}
+	if n.artBorder && !info.Stopped && n.artPos != ArtCenter && !n.dense {
+		info.Art = n.framedArt(info)
+	}
+	// Graphics art draws over a blank block of its size.
+	if info.Art == "" && info.HasArt && n.artCols > 0 {
+		info.Art = strings.TrimSuffix(strings.Repeat(strings.Repeat(" ", n.artCols)+"\n", n.ArtRows()), "\n")
+	}
+
 	artPad := 0
-	if info.HasArt && n.artCols > 0 {
-		artPad = n.artCols + 1
+	if info.Art != "" {
+		artPad = lipgloss.Width(info.Art) + 1
 	}
 
 	innerWidth := n.width - 4 - artPad
@@ -63,14 +180,23 @@ func (n *NowPlayingPanel) View(info NowPlayingInfo) string {
 		artPad = 0
 	}
 
-	prefix := ""
-	if artPad > 0 {
-		prefix = strings.Repeat(" ", artPad)
+	if info.Stopped {
+		return n.viewStopped(info, innerWidth)
+	}
+
+	// Paused dims the whole panel so the state reads at a glance.
+	titleStyle, barStyle := n.styles.NpTitle, n.styles.NpBarFilled
+	if info.Paused || info.Halted {
+		titleStyle, barStyle = n.styles.NpDim, n.styles.NpDim
 	}
 
 	// Row 1: icon + title.
 	icon := "▶"
-	if info.Paused {
+	switch {
+	case info.Halted:
+		icon = "⏹"
+		info.ElapsedSec = 0
+	case info.Paused:
 		icon = "⏸"
 	}
 	title := info.Title
@@ -78,7 +204,7 @@ func (n *NowPlayingPanel) View(info NowPlayingInfo) string {
 	if len(title) > maxTitleWidth {
 		title = title[:maxTitleWidth-1] + "…"
 	}
-	row1 := prefix + fmt.Sprintf("%s %s", icon, n.styles.NpTitle.Render(title))
+	row1 := titleStyle.Render(fmt.Sprintf("%s %s", icon, title))
 
 	// Row 2: artist — album (year).
 	albumInfo := info.Artist
@@ -88,10 +214,22 @@ func (n *NowPlayingPanel) View(info NowPlayingInfo) string {
 	if info.Year > 0 {
 		albumInfo += fmt.Sprintf(" (%d)", info.Year)
 	}
-	if len(albumInfo) > innerWidth {
+	quality := formatQuality(info)
+	if info.NoSeek {
+		quality = strings.TrimPrefix(quality+" · no seek", " · ")
+	}
+	if avail := innerWidth - len(quality) - 2; quality != "" && len(albumInfo) > avail {
+		albumInfo = albumInfo[:max(avail-1, 0)] + "…"
+	} else if len(albumInfo) > innerWidth {
 		albumInfo = albumInfo[:innerWidth-1] + "…"
 	}
-	row2 := prefix + n.styles.NpDim.Render(albumInfo)
+	row2 := n.links.wrap("album", info.AlbumID, info.Album, n.styles.NpDim.Render(albumInfo))
+	if quality != "" {
+		row2 += "  " + n.styles.NpTime.Render(quality)
+	}
+	if n.dense {
+		row1 = titleStyle.Render(truncateRunes(fmt.Sprintf("%s %s · %s", icon, info.Title, albumInfo), innerWidth))
+	}
 
 	// Row 3: seek bar with timestamps.
 	elapsed := int(info.ElapsedSec)
@@ -100,9 +238,19 @@ func (n *NowPlayingPanel) View(info NowPlayingInfo) string {
 		total = 1
 	}
 
-	elapsedStr := formatTimestamp(elapsed)
-	totalStr := formatTimestamp(total)
+	long := total >= 3600
+	elapsedStr := formatClock(elapsed, long)
+	totalStr := formatClock(total, long)
+	if info.Remaining {
+		totalStr = "-" + formatClock(total-elapsed, long)
+	}
+	if n.elapsedOnly {
+		totalStr = ""
+	}
 	timeWidth := len(elapsedStr) + len(totalStr) + 3
+	if n.elapsedOnly {
+		timeWidth = len(elapsedStr) + 1
+	}
 	barWidth := innerWidth - timeWidth
 	if barWidth < 10 {
 		barWidth = 10
@@ -116,27 +264,218 @@ func (n *NowPlayingPanel) View(info NowPlayingInfo) string {
 		progress = 0
 	}
 
-	filled := int(progress * float64(barWidth))
-	empty := barWidth - filled
-
-	bar := n.styles.NpBarFilled.Render(strings.Repeat("━", filled)) +
-		n.styles.NpBarEmpty.Render(strings.Repeat("─", empty))
+	shade := n.gradient
+	if info.Paused || info.Halted {
+		shade = nil // paused stays dim
+	}
+	bar := n.seekBar(progress, barWidth, info.Marks, total, barStyle, shade)
 
-	row3 := prefix + fmt.Sprintf("%s %s %s",
+	row3 := fmt.Sprintf("%s %s %s",
 		n.styles.NpTime.Render(elapsedStr),
 		bar,
 		n.styles.NpTime.Render(totalStr))
+	if n.elapsedOnly {
+		row3 = n.styles.NpTime.Render(elapsedStr) + " " + bar
+	}
+
+	rows := []string{row1, row2, row3}
+	if n.dense {
+		rows = []string{row1, row3}
+	}
+	box := n.styles.NpContainer
+	n.seekRow = box.GetMarginTop() + box.GetBorderTopSize() + box.GetPaddingTop() + len(rows) - 1
+	if len(info.Levels) > 0 && !info.Paused && !info.Halted {
+		rows = append(rows, n.visualizer(info.Levels, barStyle))
+	}
+	content := lipgloss.JoinVertical(n.align(), rows...)
+	if artPad > 0 {
+		if n.artPos == ArtRight {
+			content = lipgloss.NewStyle().Width(innerWidth).Render(content)
+			content = lipgloss.JoinHorizontal(lipgloss.Top, content, " ", info.Art)
+			n.rightInset = artPad
+		} else {
+			content = lipgloss.JoinHorizontal(lipgloss.Top, info.Art, " ", content)
+		}
+	}
+
+	return n.styles.NpContainer.Width(n.width).Render(content)
+}
+
+// levelGlyphs draw a band's level, from silence to full.
+var levelGlyphs = []rune(" ▁▂▃▄▅▆▇█")
+
+// visualizer draws each band level as a two-cell bar, on a decibel scale
+// from -48 dB up to full scale.
+func (n *NowPlayingPanel) visualizer(levels []float64, style lipgloss.Style) string {
+	const floorDB = -48.0
+	var b strings.Builder
+	for i, level := range levels {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		step := 0
+		if level > 0 {
+			decibels := 20 * math.Log10(level)
+			step = int(math.Round((decibels - floorDB) / -floorDB * float64(len(levelGlyphs)-1)))
+			step = min(max(step, 0), len(levelGlyphs)-1)
+		}
+		b.WriteString(strings.Repeat(string(levelGlyphs[step]), 2))
+	}
+	return style.Render(b.String())
+}
 
-	content := lipgloss.JoinVertical(lipgloss.Left, row1, row2, row3)
+// seekBar draws the bar of width cells, progress of the way played, with
+// a tick at each bookmark.
+func (n *NowPlayingPanel) seekBar(progress float64, width int, marks []float64, total int, barStyle lipgloss.Style, shade *Gradient) string {
+	cells := progress * float64(width)
+	filled := int(cells)
 
+	// A partial glyph shows how far into the head cell playback is.
+	head := ""
+	if parts := len(n.bar.Partial); parts > 0 && filled < width {
+		if step := int((cells - float64(filled)) * float64(parts+1)); step > 0 {
+			head = n.bar.Partial[step-1]
+		}
+	}
+
+	if len(marks) == 0 {
+		if head != "" {
+			return n.played(0, filled, filled, barStyle, shade) + barStyle.Render(head) +
+				n.styles.NpBarEmpty.Render(strings.Repeat(n.bar.Empty, width-filled-1))
+		}
+		return n.played(0, filled, filled, barStyle, shade) +
+			n.styles.NpBarEmpty.Render(strings.Repeat(n.bar.Empty, width-filled))
+	}
+
+	ticks := make(map[int]bool, len(marks))
+	for _, sec := range marks {
+		ticks[min(int(sec/float64(total)*float64(width)), width-1)] = true
+	}
+	var b strings.Builder
+	for i := 0; i < width; {
+		// Render runs of the same kind of cell together.
+		j := i + 1
+		for j < width && ticks[j] == ticks[i] && (j < filled) == (i < filled) {
+			j++
+		}
+		switch {
+		case ticks[i]:
+			b.WriteString(n.styles.NpTime.Render(strings.Repeat("┃", j-i)))
+		case i < filled:
+			b.WriteString(n.played(i, j-i, filled, barStyle, shade))
+		case i == filled && head != "":
+			b.WriteString(barStyle.Render(head) + n.styles.NpBarEmpty.Render(strings.Repeat(n.bar.Empty, j-i-1)))
+		default:
+			b.WriteString(n.styles.NpBarEmpty.Render(strings.Repeat(n.bar.Empty, j-i)))
+		}
+		i = j
+	}
+	return b.String()
+}
+
+// played draws count played cells from start, shaded along the filled
+// cells played so far when there's a gradient.
+func (n *NowPlayingPanel) played(start, count, filled int, style lipgloss.Style, shade *Gradient) string {
+	if shade == nil {
+		return style.Render(strings.Repeat(n.bar.Filled, count))
+	}
+	var b strings.Builder
+	for i := start; i < start+count; i++ {
+		t := 0.0
+		if filled > 1 {
+			t = float64(i) / float64(filled-1)
+		}
+		b.WriteString(style.Foreground(shade.at(t)).Render(n.bar.Filled))
+	}
+	return b.String()
+}
+
+// framedArt returns the art region inside a rounded, theme-colored frame:
+// the text art if there is any, otherwise blank cells (graphics art is drawn
+// over them) or a placeholder note while art is loading.
+func (n *NowPlayingPanel) framedArt(info NowPlayingInfo) string {
+	art := info.Art
+	if art == "" {
+		cols := n.artCols
+		if cols <= 0 {
+			cols = n.ArtRows() * 2 // the width of square text art
+		}
+		rows := make([]string, n.ArtRows())
+		for i := range rows {
+			rows[i] = strings.Repeat(" ", cols)
+		}
+		if !info.HasArt {
+			mid := len(rows) / 2
+			rows[mid] = lipgloss.PlaceHorizontal(cols, lipgloss.Center, n.styles.NpDim.Render("♪"))
+		}
+		art = strings.Join(rows, "\n")
+	}
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(n.styles.NpBarEmpty.GetForeground()).
+		Render(art)
+}
+
+// formatQuality describes what's being heard: "FLAC" or "MP3 320" for the
+// original file, "M4A → MP3" when the server transcodes.
+func formatQuality(info NowPlayingInfo) string {
+	if info.Format == "" {
+		return ""
+	}
+	src := strings.ToUpper(info.Format)
+	if info.Transcode != "" {
+		return src + " → " + strings.ToUpper(info.Transcode)
+	}
+	if info.BitRate > 0 {
+		return fmt.Sprintf("%s %d", src, info.BitRate)
+	}
+	return src
+}
+
+// viewStopped renders the panel when the queue is loaded but nothing plays.
+func (n *NowPlayingPanel) viewStopped(info NowPlayingInfo, innerWidth int) string {
+	row1 := n.styles.NpDim.Render("■ stopped")
+
+	tracks := "tracks"
+	if info.Queued == 1 {
+		tracks = "track"
+	}
+	row2 := n.styles.NpDim.Render(fmt.Sprintf("%d %s queued", info.Queued, tracks))
+
+	row3 := n.styles.NpBarEmpty.Render(strings.Repeat(n.bar.Empty, innerWidth))
+
+	content := lipgloss.JoinVertical(n.align(), row1, row2, row3)
+	if n.dense {
+		content = lipgloss.JoinVertical(n.align(), row1+n.styles.NpDim.Render(" · ")+row2, row3)
+	}
 	return n.styles.NpContainer.Width(n.width).Render(content)
 }
 
+// align is how the rows line up: centered under the full-width seek bar
+// with art_position "center", otherwise from the left.
+func (n *NowPlayingPanel) align() lipgloss.Position {
+	if n.artPos == ArtCenter {
+		return lipgloss.Center
+	}
+	return lipgloss.Left
+}
+
+// formatTimestamp formats seconds as M:SS, switching to H:MM:SS past an hour.
 func formatTimestamp(totalSec int) string {
+	return formatClock(totalSec, totalSec >= 3600)
+}
+
+// formatClock formats seconds as M:SS, or H:MM:SS when hours is set. The
+// seek bar passes hours for both ends of a long track so they line up.
+func formatClock(totalSec int, hours bool) string {
 	if totalSec < 0 {
 		totalSec = 0
 	}
+	h := totalSec / 3600
 	m := totalSec / 60
 	s := totalSec % 60
+	if hours {
+		return fmt.Sprintf("%d:%02d:%02d", h, m%60, s)
+	}
 	return fmt.Sprintf("%d:%02d", m, s)
 }
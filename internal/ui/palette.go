@@ -2,7 +2,10 @@ package ui
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/simonhull/kitsune/internal/db"
@@ -10,14 +13,34 @@ import (
 
 // PaletteResult is a selectable item in the command palette.
 type PaletteResult struct {
-	Kind     string // "artist", "album", "track"
+	Kind     string // "artist", "album", "track", "decade", "command", or "header"
 	ID       string
 	Title    string
 	Artist   string
 	Album    string
 	AlbumID  string
 	ArtistID string
-	Year     int
+	Year     int    // release year; the first year for decades
+	Count    int    // album count for decades, result count for headers
+	Arg      string // text typed after a command's name, e.g. a snapshot name
+}
+
+// paletteCommand is an action offered when the input's first word starts
+// its ID, e.g. "dup" for duplicates.
+type paletteCommand struct {
+	id    string
+	title string
+	arg   bool // takes an optional argument: the rest of the input
+}
+
+var paletteCommands = []paletteCommand{
+	{id: "duplicates", title: "Find duplicate tracks"},
+	{id: "save", title: "Save queue as snapshot", arg: true},
+	{id: "snapshots", title: "Restore a queue snapshot"},
+	{id: "bookmark", title: "Bookmark this position", arg: true},
+	{id: "bookmarks", title: "Jump to a bookmark in this track"},
+	{id: "podcasts", title: "Browse podcasts"},
+	{id: "episodes", title: "Newest podcast episodes"},
 }
 
 // Palette is the ctrl+p command palette / fuzzy finder overlay.
@@ -26,18 +49,82 @@ type Palette struct {
 	database *db.DB
 	open     bool
 	input    string
-	results  []PaletteResult
-	cursor   int
+	results  []PaletteResult // matches in search order
+	rows     []PaletteResult // results as listed, with section headers
+	cursor   int             // index into rows
 	width    int
 	height   int
+	minChars int // input length (in runes) before searching
+	sections bool
+	dense    bool     // one line per result instead of two
+	limit    int      // matching tracks fetched per page
+	kinds    []string // result kinds shown; none means all
+	// tracksOnly narrows the results to tracks until toggled off.
+	tracksOnly bool
+	// fetched counts the tracks matched so far; more is set while the
+	// last page came back full, so scrolling to the end loads another.
+	fetched int
+	more    bool
+	err     error // the last search failure, shown instead of "no results"
 }
 
-// NewPalette creates a command palette.
-func NewPalette(database *db.DB, styles *Styles) *Palette {
+// NewPalette creates a command palette that searches once the input is at
+// least minChars characters long.
+func NewPalette(database *db.DB, styles *Styles, minChars int) *Palette {
 	return &Palette{
 		styles:   styles,
 		database: database,
+		minChars: max(minChars, 1),
+		limit:    50,
+	}
+}
+
+// SetLimit sets how many matching tracks each page of results covers.
+func (p *Palette) SetLimit(n int) {
+	if n > 0 {
+		p.limit = n
+	}
+}
+
+// SetKinds restricts results to the given kinds ("artist", "album",
+// "track"); none means all.
+func (p *Palette) SetKinds(kinds []string) {
+	p.kinds = kinds
+}
+
+// ToggleTracksOnly switches between the configured kinds and tracks only,
+// re-running the search. It reports whether only tracks are shown.
+func (p *Palette) ToggleTracksOnly() bool {
+	p.tracksOnly = !p.tracksOnly
+	p.search()
+	return p.tracksOnly
+}
+
+// searchKinds returns the kinds to search for right now.
+func (p *Palette) searchKinds() []string {
+	if p.tracksOnly {
+		return []string{"track"}
 	}
+	return p.kinds
+}
+
+// SetSections sets whether results are grouped by kind under "Artists",
+// "Albums" and "Tracks" headers.
+func (p *Palette) SetSections(on bool) {
+	p.sections = on
+}
+
+// SetDense lists each result on one line, with its detail beside the title.
+func (p *Palette) SetDense(on bool) {
+	p.dense = on
+}
+
+// resultLines is how many lines each result takes.
+func (p *Palette) resultLines() int {
+	if p.dense {
+		return 1
+	}
+	return 2
 }
 
 // IsOpen returns whether the palette is visible.
@@ -50,6 +137,7 @@ func (p *Palette) Open() {
 	p.open = true
 	p.input = ""
 	p.results = nil
+	p.rows = nil
 	p.cursor = 0
 }
 
@@ -58,6 +146,7 @@ func (p *Palette) Close() {
 	p.open = false
 	p.input = ""
 	p.results = nil
+	p.rows = nil
 	p.cursor = 0
 }
 
@@ -78,52 +167,128 @@ func (p *Palette) Type(ch string) {
 	p.search()
 }
 
-// Backspace removes the last character.
+// Backspace removes the last character (rune, not byte).
 func (p *Palette) Backspace() {
 	if len(p.input) > 0 {
-		p.input = p.input[:len(p.input)-1]
+		_, size := utf8.DecodeLastRuneInString(p.input)
+		p.input = p.input[:len(p.input)-size]
 		p.search()
 	}
 }
 
-// CursorUp moves selection up.
+// CursorUp moves selection up, over section headers.
 func (p *Palette) CursorUp() {
-	if p.cursor > 0 {
-		p.cursor--
+	for i := p.cursor - 1; i >= 0; i-- {
+		if p.rows[i].Kind != "header" {
+			p.cursor = i
+			return
+		}
 	}
 }
 
-// CursorDown moves selection down.
+// CursorDown moves selection down, over section headers, loading the next
+// page of matches when it reaches the last result.
 func (p *Palette) CursorDown() {
-	if p.cursor < len(p.results)-1 {
-		p.cursor++
+	for i := p.cursor + 1; i < len(p.rows); i++ {
+		if p.rows[i].Kind != "header" {
+			p.cursor = i
+			break
+		}
+	}
+	if p.cursor == len(p.rows)-1 && p.more {
+		p.loadMore()
 	}
 }
 
 // Selected returns the currently highlighted result, or nil.
 func (p *Palette) Selected() *PaletteResult {
-	if p.cursor >= 0 && p.cursor < len(p.results) {
-		return &p.results[p.cursor]
+	if p.cursor >= 0 && p.cursor < len(p.rows) && p.rows[p.cursor].Kind != "header" {
+		return &p.rows[p.cursor]
 	}
 	return nil
 }
 
+// TrackIDs returns the IDs of up to limit matching tracks in result order,
+// loading further pages as needed.
+func (p *Palette) TrackIDs(limit int) []string {
+	for p.more && p.fetched < limit {
+		p.loadMore()
+	}
+	var ids []string
+	for _, r := range p.results {
+		if r.Kind == "track" && len(ids) < limit {
+			ids = append(ids, r.ID)
+		}
+	}
+	return ids
+}
+
+// tooShort reports whether the input is below the search threshold.
+func (p *Palette) tooShort() bool {
+	return utf8.RuneCountInString(p.input) < p.minChars
+}
+
 func (p *Palette) search() {
 	p.cursor = 0
-	if p.input == "" {
-		p.results = nil
+	p.results = nil
+	p.rows = nil
+	p.fetched = 0
+	p.more = false
+	p.err = nil
+	if p.input == "" || p.tooShort() {
 		return
 	}
+	defer p.arrange()
+
+	word, arg, _ := strings.Cut(strings.TrimSpace(p.input), " ")
+	if word = strings.ToLower(word); utf8.RuneCountInString(word) >= 3 {
+		arg = strings.TrimSpace(arg)
+		for _, c := range paletteCommands {
+			if !strings.HasPrefix(c.id, word) || (arg != "" && !c.arg) {
+				continue
+			}
+			title := c.title
+			if arg != "" {
+				title += fmt.Sprintf(" %q", arg)
+			}
+			p.results = append(p.results, PaletteResult{Kind: "command", ID: c.id, Title: title, Arg: arg})
+		}
+	}
 
-	dbResults, err := p.database.Search(p.input, 50)
+	// "80s" or "1980s" offers the decade ahead of any text matches.
+	if from, ok := parseDecade(p.input); ok {
+		if albums, err := p.database.AlbumsByYearRange(from, from+9); err == nil && len(albums) > 0 {
+			p.results = append(p.results, PaletteResult{
+				Kind:  "decade",
+				Title: fmt.Sprintf("%ds", from),
+				Year:  from,
+				Count: len(albums),
+			})
+		}
+	}
+
+	p.loadMore()
+}
+
+// loadMore fetches the next page of library matches and appends the ones
+// not already listed.
+func (p *Palette) loadMore() {
+	dbResults, matched, err := p.database.SearchPage(p.input, p.limit, p.fetched, p.searchKinds())
 	if err != nil {
-		p.results = nil
+		p.more = false
+		p.err = err
 		return
 	}
 
-	p.results = make([]PaletteResult, len(dbResults))
-	for i, r := range dbResults {
-		p.results[i] = PaletteResult{
+	seen := make(map[string]bool, len(p.results))
+	for _, r := range p.results {
+		seen[r.Kind+"\x00"+r.ID] = true
+	}
+	for _, r := range dbResults {
+		if seen[r.Kind+"\x00"+r.ID] {
+			continue
+		}
+		p.results = append(p.results, PaletteResult{
 			Kind:     r.Kind,
 			ID:       r.ID,
 			Title:    r.Title,
@@ -132,8 +297,86 @@ func (p *Palette) search() {
 			AlbumID:  r.AlbumID,
 			ArtistID: r.ArtistID,
 			Year:     r.Year,
+		})
+	}
+	p.fetched += matched
+	p.more = matched == p.limit
+	p.arrange()
+}
+
+// paletteSections are the kinds grouped under headers, in display order.
+var paletteSections = []struct{ kind, title string }{
+	{"artist", "Artists"},
+	{"album", "Albums"},
+	{"track", "Tracks"},
+}
+
+// arrange builds the listed rows from the results: as they are, or with
+// commands and decades first and the rest grouped under section headers.
+// The cursor stays on the same result.
+func (p *Palette) arrange() {
+	var selected *PaletteResult
+	if sel := p.Selected(); sel != nil {
+		r := *sel
+		selected = &r
+	}
+
+	if !p.sections {
+		p.rows = p.results
+	} else {
+		p.rows = nil
+		for _, r := range p.results {
+			if r.Kind == "command" || r.Kind == "decade" {
+				p.rows = append(p.rows, r)
+			}
+		}
+		for _, sec := range paletteSections {
+			start := len(p.rows)
+			p.rows = append(p.rows, PaletteResult{Kind: "header", Title: sec.title})
+			for _, r := range p.results {
+				if r.Kind == sec.kind {
+					p.rows = append(p.rows, r)
+				}
+			}
+			if n := len(p.rows) - start - 1; n > 0 {
+				p.rows[start].Count = n
+			} else {
+				p.rows = p.rows[:start]
+			}
 		}
 	}
+
+	p.cursor = 0
+	for i, r := range p.rows {
+		if selected != nil && r.Kind == selected.Kind && r.ID == selected.ID && r.Title == selected.Title {
+			p.cursor = i
+			return
+		}
+	}
+	if len(p.rows) > 0 && p.rows[0].Kind == "header" {
+		p.cursor = 1
+	}
+}
+
+// parseDecade reads "80s" or "1980s" as the decade's first year. Two-digit
+// decades below 30 are taken as 2000s.
+func parseDecade(input string) (int, bool) {
+	digits, ok := strings.CutSuffix(strings.ToLower(strings.TrimSpace(input)), "s")
+	if !ok || (len(digits) != 2 && len(digits) != 4) {
+		return 0, false
+	}
+	year, err := strconv.Atoi(digits)
+	if err != nil || year%10 != 0 {
+		return 0, false
+	}
+	if len(digits) == 2 {
+		if year < 30 {
+			year += 2000
+		} else {
+			year += 1900
+		}
+	}
+	return year, true
 }
 
 // View renders the palette as a centered panel in the content area.
@@ -152,18 +395,24 @@ func (p *Palette) View() string {
 	}
 	innerWidth := palWidth - 6 // border(2) + padding(4)
 
-	// Each result is 2 lines tall; budget for input + divider + results.
+	// Budget for input + divider + results, each resultLines tall.
 	maxResultLines := p.height - 10
 	if maxResultLines < 6 {
 		maxResultLines = 6
 	}
-	maxResults := maxResultLines / 2
+	maxResults := maxResultLines / p.resultLines()
 
 	// Input row.
 	prompt := p.styles.NpBarFilled.Render("❯ ")
+	if p.tracksOnly {
+		prompt = p.styles.NpBarFilled.Render("♪ ❯ ")
+	}
+	// Keep the tail of long input visible, dropping whole runes so
+	// multi-byte and double-width characters are never split.
 	inputText := p.input
-	if len(inputText) > innerWidth-4 {
-		inputText = inputText[len(inputText)-innerWidth+4:]
+	for inputText != "" && lipgloss.Width(inputText) > innerWidth-4 {
+		_, size := utf8.DecodeRuneInString(inputText)
+		inputText = inputText[size:]
 	}
 	cursor := p.styles.NpTitle.Render("█")
 	inputRow := prompt + inputText + cursor
@@ -176,10 +425,14 @@ func (p *Palette) View() string {
 	rows = append(rows, inputRow)
 	rows = append(rows, divider)
 
-	if len(p.results) == 0 && p.input != "" {
+	if p.input != "" && p.tooShort() {
+		rows = append(rows, p.styles.Dim.Render("  keep typing…"))
+	} else if p.err != nil {
+		rows = append(rows, p.styles.Error.Render(truncateRunes("  search failed: "+p.err.Error(), innerWidth)))
+	} else if len(p.rows) == 0 && p.input != "" {
 		rows = append(rows, p.styles.Dim.Render("  no results"))
-	} else if len(p.results) == 0 {
-		rows = append(rows, p.styles.Dim.Render("  type to search artists, albums, tracks"))
+	} else if len(p.rows) == 0 {
+		rows = append(rows, p.styles.Dim.Render("  type to search artists, albums, tracks, a decade like 90s, or a command like save"))
 	}
 
 	// Scrolled window of results.
@@ -188,16 +441,23 @@ func (p *Palette) View() string {
 		offset = p.cursor - maxResults + 1
 	}
 	end := offset + maxResults
-	if end > len(p.results) {
-		end = len(p.results)
+	if end > len(p.rows) {
+		end = len(p.rows)
 	}
 
 	for i := offset; i < end; i++ {
-		r := p.results[i]
+		r := p.rows[i]
 		line := p.renderResult(r, i == p.cursor, innerWidth)
 		rows = append(rows, line)
 	}
 
+	if p.more {
+		rows = append(rows, p.styles.Dim.Render("  more below…"))
+	}
+	if slices.ContainsFunc(p.results, func(r PaletteResult) bool { return r.Kind == "track" }) {
+		rows = append(rows, p.styles.Dim.Render("  tab plays all matching tracks · ctrl+t tracks only"))
+	}
+
 	content := lipgloss.JoinVertical(lipgloss.Left, rows...)
 
 	box := paletteBoxStyle(p.styles).
@@ -212,6 +472,10 @@ func (p *Palette) View() string {
 }
 
 func (p *Palette) renderResult(r PaletteResult, selected bool, maxWidth int) string {
+	if r.Kind == "header" {
+		return p.styles.Dim.Render(fmt.Sprintf("  %s (%d)", r.Title, r.Count))
+	}
+
 	var icon, primary, secondary string
 
 	switch r.Kind {
@@ -231,6 +495,14 @@ func (p *Palette) renderResult(r PaletteResult, selected bool, maxWidth int) str
 		icon = "♪ "
 		primary = r.Title
 		secondary = r.Artist + " — " + r.Album
+	case "decade":
+		icon = "📅 "
+		primary = r.Title
+		secondary = fmt.Sprintf("%d albums", r.Count)
+	case "command":
+		icon = "⚙ "
+		primary = r.Title
+		secondary = "command"
 	}
 
 	// Truncate.
@@ -244,11 +516,18 @@ func (p *Palette) renderResult(r PaletteResult, selected bool, maxWidth int) str
 		secondary = secondary[:secWidth-1] + "…"
 	}
 
-	line := fmt.Sprintf("  %s%s\n  %s%s",
-		icon,
-		p.styles.NpTitle.Render(primary),
-		strings.Repeat(" ", len(icon)),
-		p.styles.Dim.Render(secondary))
+	var line string
+	if p.dense {
+		// Title first; the detail gets whatever width is left.
+		secondary = truncateRunes(secondary, max(availWidth-lipgloss.Width(primary)-2, 1))
+		line = fmt.Sprintf("  %s%s  %s", icon, p.styles.NpTitle.Render(primary), p.styles.Dim.Render(secondary))
+	} else {
+		line = fmt.Sprintf("  %s%s\n  %s%s",
+			icon,
+			p.styles.NpTitle.Render(primary),
+			strings.Repeat(" ", len(icon)),
+			p.styles.Dim.Render(secondary))
+	}
 
 	if selected {
 		return paletteCursorStyle(p.styles).Width(maxWidth + 4).Render(line)
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/simonhull/kitsune/internal/db"
+)
+
+func newTestPalette(t *testing.T) *Palette {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	database, err := db.Open(nil)
+	if err != nil {
+		t.Fatalf("opening database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	styles := NewStyles(DefaultTheme())
+	p := NewPalette(database, &styles, 1)
+	p.SetSize(80, 24)
+	p.Open()
+	return p
+}
+
+func TestPaletteWideInput(t *testing.T) {
+	p := newTestPalette(t)
+	for _, ch := range []string{"東", "京", "🦊", "e", "́"} {
+		p.Type(ch)
+	}
+	if got, want := p.Input(), "東京🦊é"; got != want {
+		t.Fatalf("Input() = %q, want %q", got, want)
+	}
+
+	// Backspace takes off a rune at a time, never leaving a partial one.
+	for _, want := range []string{"東京🦊e", "東京🦊", "東京", "東", ""} {
+		p.Backspace()
+		if got := p.Input(); got != want || !utf8.ValidString(got) {
+			t.Fatalf("after Backspace Input() = %q, want %q", got, want)
+		}
+	}
+	p.Backspace() // nothing left to remove
+	if got := p.Input(); got != "" {
+		t.Fatalf("Backspace on empty input left %q", got)
+	}
+}
+
+func TestPaletteViewLongWideInput(t *testing.T) {
+	p := newTestPalette(t)
+	// Far wider than the palette, so the view shows only the tail.
+	for range 40 {
+		p.Type("狐")
+		p.Type("🦊")
+	}
+	view := p.View()
+	if !utf8.ValidString(view) {
+		t.Fatal("View() split a rune")
+	}
+	for i, line := range strings.Split(view, "\n") {
+		if w := lipgloss.Width(line); w > 80 {
+			t.Errorf("line %d is %d cells wide, over the 80 available", i, w)
+		}
+	}
+	if !strings.Contains(view, "狐🦊█") {
+		t.Error("View() doesn't end the input with its last characters and the cursor")
+	}
+}
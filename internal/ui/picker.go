@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// PickerItem is one choice in a Picker.
+type PickerItem struct {
+	ID     string
+	Label  string
+	Detail string // dim text after the label, e.g. a track count
+}
+
+// Picker is a small overlay for choosing one item from a list, such as the
+// playlist to add tracks to.
+type Picker struct {
+	styles *Styles
+	open   bool
+	title  string
+	items  []PickerItem
+	cursor int
+	width  int
+	height int
+}
+
+// NewPicker creates a picker overlay.
+func NewPicker(styles *Styles) *Picker {
+	return &Picker{styles: styles}
+}
+
+// IsOpen returns whether the picker is visible.
+func (p *Picker) IsOpen() bool {
+	return p.open
+}
+
+// Open shows the picker with the given title and items.
+func (p *Picker) Open(title string, items []PickerItem) {
+	p.open = true
+	p.title = title
+	p.items = items
+	p.cursor = 0
+}
+
+// Close hides the picker.
+func (p *Picker) Close() {
+	p.open = false
+	p.items = nil
+	p.cursor = 0
+}
+
+// SetSize updates the available dimensions for the overlay.
+func (p *Picker) SetSize(width, height int) {
+	p.width = width
+	p.height = height
+}
+
+// CursorUp moves selection up.
+func (p *Picker) CursorUp() {
+	if p.cursor > 0 {
+		p.cursor--
+	}
+}
+
+// CursorDown moves selection down.
+func (p *Picker) CursorDown() {
+	if p.cursor < len(p.items)-1 {
+		p.cursor++
+	}
+}
+
+// Selected returns the highlighted item, or nil.
+func (p *Picker) Selected() *PickerItem {
+	if p.cursor >= 0 && p.cursor < len(p.items) {
+		return &p.items[p.cursor]
+	}
+	return nil
+}
+
+// Cursor returns the index of the highlighted item.
+func (p *Picker) Cursor() int {
+	return p.cursor
+}
+
+// Remove drops the item at idx, keeping the cursor in range.
+func (p *Picker) Remove(idx int) {
+	if idx < 0 || idx >= len(p.items) {
+		return
+	}
+	p.items = append(p.items[:idx], p.items[idx+1:]...)
+	p.cursor = max(min(p.cursor, len(p.items)-1), 0)
+}
+
+// View renders the picker as a centered panel in the content area.
+func (p *Picker) View() string {
+	if !p.open {
+		return ""
+	}
+
+	boxWidth := min(max(p.width*40/100, 30), p.width-4)
+	innerWidth := boxWidth - 4 // border(2) + padding(2)
+
+	rows := []string{
+		p.styles.QueueHeader.Padding(0).Render(p.title),
+		p.styles.Dim.Render(strings.Repeat("─", innerWidth)),
+	}
+	if len(p.items) == 0 {
+		rows = append(rows, p.styles.Dim.Render("  nothing to pick"))
+	}
+
+	maxItems := max(p.height-8, 3)
+	offset := 0
+	if p.cursor >= maxItems {
+		offset = p.cursor - maxItems + 1
+	}
+	end := min(offset+maxItems, len(p.items))
+
+	for i := offset; i < end; i++ {
+		item := p.items[i]
+		label := item.Label
+		if avail := innerWidth - 2 - lipgloss.Width(item.Detail) - 1; lipgloss.Width(label) > avail {
+			label = truncateRunes(label, max(avail, 1))
+		}
+		line := "  " + label
+		if item.Detail != "" {
+			line += " " + p.styles.Dim.Render(item.Detail)
+		}
+		if i == p.cursor {
+			line = paletteCursorStyle(p.styles).Width(innerWidth).Render(line)
+		}
+		rows = append(rows, line)
+	}
+	rows = append(rows, "", p.styles.Dim.Render("enter: choose  esc: cancel"))
+
+	box := paletteBoxStyle(p.styles).
+		Width(boxWidth).
+		Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+
+	return lipgloss.Place(p.width, p.height,
+		lipgloss.Center, lipgloss.Center,
+		box,
+		lipgloss.WithWhitespaceChars(" "))
+}
+
+// truncateRunes shortens s to at most width cells, ending in "…".
+func truncateRunes(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
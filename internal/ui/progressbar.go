@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ProgressBar is the set of glyphs the seek bar is drawn with.
+type ProgressBar struct {
+	Filled string
+	Empty  string
+	// Partial are glyphs for a part-played cell at the head of the bar, from
+	// least to most filled; none means the bar moves a whole cell at a time.
+	Partial []string
+}
+
+// progressBars are the named bar styles for [ui] progressbar.
+var progressBars = map[string]ProgressBar{
+	"line":  {Filled: "━", Empty: "─"},
+	"block": {Filled: "█", Empty: "░", Partial: []string{"▏", "▎", "▍", "▌", "▋", "▊", "▉"}},
+	// Braille fills the left column of dots bottom up, then the right.
+	"braille": {Filled: "⣿", Empty: "⣀", Partial: []string{"⡀", "⡄", "⡆", "⡇", "⣇", "⣧", "⣷"}},
+}
+
+// NewProgressBar returns the named style ("line", "block" or "braille";
+// empty means line) with the filled and empty glyphs replaced where given.
+// Glyphs must be one cell wide so the bar's width stays right.
+func NewProgressBar(style, filled, empty string) (ProgressBar, error) {
+	if style == "" {
+		style = "line"
+	}
+	bar, ok := progressBars[style]
+	if !ok {
+		return progressBars["line"], fmt.Errorf("unknown progressbar %q", style)
+	}
+	for _, g := range []string{filled, empty} {
+		if g != "" && lipgloss.Width(g) != 1 {
+			return progressBars["line"], fmt.Errorf("progress glyph %q is not one cell wide", g)
+		}
+	}
+	if filled != "" {
+		// The partial glyphs only blend with the stock filled glyph.
+		bar.Filled, bar.Partial = filled, nil
+	}
+	if empty != "" {
+		bar.Empty = empty
+	}
+	return bar, nil
+}
+
+// Gradient shades the played part of the seek bar from one color to
+// another, in RGB.
+type Gradient struct {
+	from, to [3]float64
+}
+
+// NewGradient returns a gradient from the theme's accent to a lighter mix
+// of accent and foreground, or nil when the terminal lacks truecolor or the
+// theme's colors aren't hex, leaving the bar one solid color.
+func NewGradient(t Theme) *Gradient {
+	if !detectTruecolor() {
+		return nil
+	}
+	from, ok := parseHexColor(t.Accent)
+	if !ok {
+		return nil
+	}
+	fg, ok := parseHexColor(t.Fg)
+	if !ok {
+		return nil
+	}
+	g := &Gradient{from: from}
+	for i := range g.to {
+		g.to[i] = from[i] + (fg[i]-from[i])*0.6
+	}
+	return g
+}
+
+// at returns the color t of the way along, 0 to 1.
+func (g *Gradient) at(t float64) lipgloss.Color {
+	var c [3]int
+	for i := range c {
+		c[i] = int(math.Round(g.from[i] + (g.to[i]-g.from[i])*t))
+	}
+	return lipgloss.Color(fmt.Sprintf("#%02x%02x%02x", c[0], c[1], c[2]))
+}
+
+// parseHexColor reads a "#rrggbb" color.
+func parseHexColor(c lipgloss.Color) ([3]float64, bool) {
+	var r, g, b int
+	if len(c) != 7 {
+		return [3]float64{}, false
+	}
+	if _, err := fmt.Sscanf(string(c), "#%02x%02x%02x", &r, &g, &b); err != nil {
+		return [3]float64{}, false
+	}
+	return [3]float64{float64(r), float64(g), float64(b)}, true
+}
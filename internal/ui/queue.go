@@ -2,7 +2,19 @@ package ui
 
 import (
 	"fmt"
+	"math/rand/v2"
+	"slices"
 	"strings"
+	"time"
+)
+
+// FollowMode controls how the queue keeps the playing track in view.
+type FollowMode string
+
+const (
+	FollowVisible FollowMode = "visible" // scroll just enough to show it
+	FollowCenter  FollowMode = "center"  // keep it centered in the list
+	FollowOff     FollowMode = "off"     // start with follow disabled
 )
 
 // QueueTrack is a track in the playback queue.
@@ -12,9 +24,13 @@ type QueueTrack struct {
 	Artist     string
 	Album      string
 	AlbumID    string
+	ArtistID   string
 	Year       int
 	DurationMs int
 	Format     string
+	BitRate    int    // kbps, 0 if unknown
+	CoverArt   string // the server's cover art ID, "" if unknown
+	Podcast    bool   // a podcast episode; ID is its stream ID
 }
 
 // Queue is the playback queue panel.
@@ -27,11 +43,51 @@ type Queue struct {
 	width   int
 	height  int
 	focused bool
+	// follow moves the cursor to the playing track whenever it advances.
+	follow     bool
+	followMode FollowMode
+	// Manual scrolling suspends follow until followIdle has passed since touched.
+	followIdle time.Duration
+	touched    time.Time
+	// maxLen caps the queue; already-played tracks are trimmed to fit (0 = unlimited).
+	maxLen int
+	// capped describes the last time an add ran into maxLen, until taken.
+	capped QueueCap
+	// unshuffled is the order before ToggleShuffle shuffled, nil when unshuffled.
+	unshuffled []QueueTrack
 }
 
 // NewQueue creates an empty queue.
 func NewQueue(styles *Styles) *Queue {
-	return &Queue{styles: styles, current: -1}
+	return &Queue{styles: styles, current: -1, follow: true, followMode: FollowVisible}
+}
+
+// SetFollow configures follow mode and how long manual scrolling suspends it.
+func (q *Queue) SetFollow(mode FollowMode, idle time.Duration) {
+	switch mode {
+	case FollowCenter:
+		q.followMode = FollowCenter
+	case FollowOff:
+		q.follow = false
+	default:
+		q.followMode = FollowVisible
+	}
+	q.followIdle = idle
+}
+
+// SetMaxLen caps the queue length (0 = unlimited).
+func (q *Queue) SetMaxLen(n int) {
+	q.maxLen = max(n, 0)
+}
+
+// ToggleFollow flips whether the view tracks the playing track, returning the new state.
+func (q *Queue) ToggleFollow() bool {
+	q.follow = !q.follow
+	q.touched = time.Time{}
+	if q.follow {
+		q.followCurrent()
+	}
+	return q.follow
 }
 
 // SetSize updates the panel dimensions.
@@ -50,6 +106,11 @@ func (q *Queue) OffsetVal() int {
 	return q.offset
 }
 
+// Cursor returns the selected row.
+func (q *Queue) Cursor() int {
+	return q.cursor
+}
+
 // SetCursor sets the cursor to a specific index (clamped to valid range).
 func (q *Queue) SetCursor(idx int) {
 	if idx < 0 {
@@ -62,6 +123,7 @@ func (q *Queue) SetCursor(idx int) {
 		idx = 0
 	}
 	q.cursor = idx
+	q.touch()
 	q.scrollIntoView()
 }
 
@@ -69,11 +131,18 @@ func (q *Queue) SetCursor(idx int) {
 
 func (q *Queue) Replace(tracks []QueueTrack, startIdx int) {
 	q.tracks = tracks
+	q.unshuffled = nil
 	q.current = startIdx
 	q.cursor = startIdx
 	q.scrollIntoView()
 }
 
+// Append adds tracks to the end of the queue.
+func (q *Queue) Append(tracks ...QueueTrack) {
+	q.tracks = append(q.tracks, tracks...)
+	q.trim()
+}
+
 func (q *Queue) Len() int        { return len(q.tracks) }
 
 func (q *Queue) Current() *QueueTrack {
@@ -86,12 +155,47 @@ func (q *Queue) Current() *QueueTrack {
 func (q *Queue) Next() *QueueTrack {
 	if q.current+1 < len(q.tracks) {
 		q.current++
+		q.followCurrent()
 		return &q.tracks[q.current]
 	}
 	q.current = -1
 	return nil
 }
 
+// Skip moves current by n tracks (negative to go back), clamped to the queue.
+// Returns the new current track, or nil if nothing is playing or it didn't move.
+func (q *Queue) Skip(n int) *QueueTrack {
+	if q.current < 0 || len(q.tracks) == 0 {
+		return nil
+	}
+	target := min(max(q.current+n, 0), len(q.tracks)-1)
+	if target == q.current {
+		return nil
+	}
+	q.current = target
+	q.followCurrent()
+	return &q.tracks[q.current]
+}
+
+// Selected returns the track under the cursor, or nil.
+func (q *Queue) Selected() *QueueTrack {
+	if q.cursor >= 0 && q.cursor < len(q.tracks) {
+		return &q.tracks[q.cursor]
+	}
+	return nil
+}
+
+// PlayAt makes the track at idx current and moves the cursor to it.
+func (q *Queue) PlayAt(idx int) *QueueTrack {
+	if idx < 0 || idx >= len(q.tracks) {
+		return nil
+	}
+	q.current = idx
+	q.cursor = idx
+	q.scrollIntoView()
+	return &q.tracks[q.current]
+}
+
 func (q *Queue) JumpTo() *QueueTrack {
 	if q.cursor >= 0 && q.cursor < len(q.tracks) {
 		q.current = q.cursor
@@ -132,6 +236,7 @@ func (q *Queue) MoveUp() {
 		q.current++
 	}
 	q.cursor--
+	q.touch()
 	q.scrollIntoView()
 }
 
@@ -146,6 +251,7 @@ func (q *Queue) MoveDown() {
 		q.current--
 	}
 	q.cursor++
+	q.touch()
 	q.scrollIntoView()
 }
 
@@ -154,6 +260,7 @@ func (q *Queue) MoveDown() {
 func (q *Queue) CursorUp() {
 	if q.cursor > 0 {
 		q.cursor--
+		q.touch()
 		q.scrollIntoView()
 	}
 }
@@ -161,6 +268,7 @@ func (q *Queue) CursorUp() {
 func (q *Queue) CursorDown() {
 	if q.cursor < len(q.tracks)-1 {
 		q.cursor++
+		q.touch()
 		q.scrollIntoView()
 	}
 }
@@ -176,8 +284,16 @@ func (q *Queue) View() string {
 
 	var b strings.Builder
 
-	header := q.styles.QueueHeader.Width(q.width).Render(
-		fmt.Sprintf("Queue (%d)", len(q.tracks)-q.currentOrZero()))
+	title := fmt.Sprintf("Queue (%d)", len(q.tracks)-q.currentOrZero())
+	if q.Shuffled() {
+		title += q.styles.QueueDim.Render(" · shuffled")
+	}
+	if !q.follow {
+		title += q.styles.QueueDim.Render(" · follow off")
+	} else if q.suspended() {
+		title += q.styles.QueueDim.Render(" · follow paused")
+	}
+	header := q.styles.QueueHeader.Width(q.width).Render(title)
 	b.WriteString(header)
 	b.WriteByte('\n')
 
@@ -238,6 +354,187 @@ func (q *Queue) currentOrZero() int {
 	return 0
 }
 
+// trim drops already-played tracks from the front until the queue fits
+// maxLen. Unplayed tracks are never dropped, so the queue can still exceed
+// the cap when everything in it is upcoming.
+func (q *Queue) trim() {
+	if q.maxLen <= 0 || len(q.tracks) <= q.maxLen {
+		return
+	}
+	dropped := 0
+	if q.current > 0 {
+		dropped = min(len(q.tracks)-q.maxLen, q.current)
+		q.dropFront(dropped)
+	}
+	if over := len(q.tracks) > q.maxLen; over || dropped > 0 {
+		q.capped = QueueCap{Limit: q.maxLen, Dropped: dropped, Over: over}
+	}
+}
+
+// QueueCap describes an add that ran into the queue's length limit.
+type QueueCap struct {
+	Limit   int
+	Dropped int  // played tracks dropped to make room
+	Over    bool // still over the limit, with nothing played left to drop
+}
+
+// TakeCap returns the last add that ran into the queue limit, then forgets
+// it. ok is false when nothing has hit the limit since.
+func (q *Queue) TakeCap() (c QueueCap, ok bool) {
+	c, q.capped = q.capped, QueueCap{}
+	return c, c.Limit > 0
+}
+
+// DropPlayed removes every track before the current one.
+func (q *Queue) DropPlayed() {
+	if q.current > 0 {
+		q.dropFront(q.current)
+	}
+}
+
+// Upcoming returns how many tracks are queued after the current one.
+func (q *Queue) Upcoming() int {
+	if q.current < 0 {
+		return 0
+	}
+	return len(q.tracks) - q.current - 1
+}
+
+// IDs returns the IDs of every queued track.
+func (q *Queue) IDs() []string {
+	ids := make([]string, len(q.tracks))
+	for i, t := range q.tracks {
+		ids[i] = t.ID
+	}
+	return ids
+}
+
+// AlbumIDs returns each album with a track in the queue, once, in queue order.
+func (q *Queue) AlbumIDs() []string {
+	var ids []string
+	for _, t := range q.tracks {
+		if !slices.Contains(ids, t.AlbumID) {
+			ids = append(ids, t.AlbumID)
+		}
+	}
+	return ids
+}
+
+// CurrentIndex returns the index of the playing track, or -1.
+func (q *Queue) CurrentIndex() int {
+	return q.current
+}
+
+// Shuffled reports whether the queue is in shuffled order.
+func (q *Queue) Shuffled() bool {
+	return q.unshuffled != nil
+}
+
+// ToggleShuffle shuffles the tracks after the current one, or restores the
+// order from before the shuffle. Restoring keeps the current track playing;
+// tracks removed meanwhile stay gone and tracks added meanwhile go at the
+// end. Returns whether the queue is now shuffled.
+func (q *Queue) ToggleShuffle() bool {
+	if q.unshuffled != nil {
+		q.unshuffle()
+		return false
+	}
+	if len(q.tracks) == 0 {
+		return false
+	}
+	q.unshuffled = slices.Clone(q.tracks)
+	upcoming := q.tracks[q.current+1:]
+	rand.Shuffle(len(upcoming), func(i, j int) { upcoming[i], upcoming[j] = upcoming[j], upcoming[i] })
+	q.cursor = max(q.current, 0)
+	q.scrollIntoView()
+	return true
+}
+
+// unshuffle puts the queue back in its pre-shuffle order.
+func (q *Queue) unshuffle() {
+	// Count what's still queued so removed tracks aren't restored.
+	remaining := make(map[string]int, len(q.tracks))
+	for _, t := range q.tracks {
+		remaining[t.ID]++
+	}
+	// The current track's occurrence among same-ID entries, to find it again.
+	nth := -1
+	if q.current >= 0 {
+		for _, t := range q.tracks[:q.current+1] {
+			if t.ID == q.tracks[q.current].ID {
+				nth++
+			}
+		}
+	}
+
+	restored := make([]QueueTrack, 0, len(q.tracks))
+	for _, t := range q.unshuffled {
+		if remaining[t.ID] > 0 {
+			remaining[t.ID]--
+			restored = append(restored, t)
+		}
+	}
+	// Whatever is left was added since the shuffle; keep its queue order.
+	for _, t := range q.tracks {
+		if remaining[t.ID] > 0 {
+			remaining[t.ID]--
+			restored = append(restored, t)
+		}
+	}
+
+	if q.current >= 0 {
+		id := q.tracks[q.current].ID
+		for i, t := range restored {
+			if t.ID == id {
+				if nth == 0 {
+					q.current = i
+					break
+				}
+				nth--
+			}
+		}
+	}
+	q.tracks = restored
+	q.unshuffled = nil
+	q.cursor = max(q.current, 0)
+	q.scrollIntoView()
+}
+
+// dropFront removes the first n tracks, keeping current, cursor and offset
+// pointing at the same entries.
+func (q *Queue) dropFront(n int) {
+	q.tracks = slices.Delete(q.tracks, 0, n)
+	q.current -= n
+	q.cursor = max(q.cursor-n, 0)
+	q.offset = max(q.offset-n, 0)
+	q.scrollIntoView()
+}
+
+// followCurrent moves the cursor to the playing track when follow mode is on
+// and the user hasn't scrolled the queue recently.
+func (q *Queue) followCurrent() {
+	if !q.follow || q.current < 0 || q.suspended() {
+		return
+	}
+	q.cursor = q.current
+	if q.followMode == FollowCenter && q.height > 2 {
+		listHeight := q.height - 2
+		q.offset = max(min(q.current-listHeight/2, len(q.tracks)-listHeight), 0)
+		return
+	}
+	q.scrollIntoView()
+}
+
+// touch records manual navigation, suspending follow for a while.
+func (q *Queue) touch() {
+	q.touched = time.Now()
+}
+
+// suspended reports whether recent manual navigation is holding off follow.
+func (q *Queue) suspended() bool {
+	return !q.touched.IsZero() && time.Since(q.touched) < q.followIdle
+}
+
 func (q *Queue) scrollIntoView() {
 	if q.height <= 2 {
 		return
@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+)
+
+// queueIDs returns the IDs of the queued tracks, in order.
+func queueIDs(q *Queue) []string {
+	ids := make([]string, len(q.tracks))
+	for i, t := range q.tracks {
+		ids[i] = t.ID
+	}
+	return ids
+}
+
+func TestShuffleUnshuffle(t *testing.T) {
+	// "b" is queued twice, so the current entry has to be told apart from
+	// the other one by position, not just ID.
+	original := []string{"a", "b", "c", "d", "b", "e", "f", "g", "h"}
+	tracks := make([]QueueTrack, len(original))
+	for i, id := range original {
+		tracks[i] = QueueTrack{ID: id, Title: id}
+	}
+	styles := NewStyles(DefaultTheme())
+
+	for _, skip := range []int{0, 1, 3} {
+		q := NewQueue(&styles)
+		q.Replace(slices.Clone(tracks), 1)
+
+		if !q.ToggleShuffle() {
+			t.Fatal("ToggleShuffle() = false, want shuffled")
+		}
+		if got := q.Current(); got == nil || got.ID != "b" || q.CurrentIndex() != 1 {
+			t.Fatalf("shuffling moved the current track to %v at %d", got, q.CurrentIndex())
+		}
+		if got := queueIDs(q); !slices.Equal(got[:2], original[:2]) {
+			t.Fatalf("shuffling reordered played tracks: %v", got)
+		}
+
+		// Play on into the shuffled order before restoring it.
+		q.Skip(skip)
+		playing := *q.Current()
+		playingIdx := q.CurrentIndex()
+		nth := 0
+		for _, tr := range q.tracks[:playingIdx] {
+			if tr.ID == playing.ID {
+				nth++
+			}
+		}
+
+		if q.ToggleShuffle() {
+			t.Fatal("ToggleShuffle() = true, want unshuffled")
+		}
+		if got := queueIDs(q); !slices.Equal(got, original) {
+			t.Fatalf("after skipping %d, unshuffled order %v, want %v", skip, got, original)
+		}
+		cur := q.Current()
+		if cur == nil || cur.ID != playing.ID {
+			t.Fatalf("after skipping %d, current is %v, want %s", skip, cur, playing.ID)
+		}
+		// The same entry is current, not another with its ID.
+		seen := 0
+		for _, tr := range q.tracks[:q.CurrentIndex()] {
+			if tr.ID == playing.ID {
+				seen++
+			}
+		}
+		if seen != nth {
+			t.Errorf("after skipping %d, current is occurrence %d of %s, want %d", skip, seen, playing.ID, nth)
+		}
+	}
+}
+
+// queueTracks returns a track for each ID.
+func queueTracks(ids ...string) []QueueTrack {
+	tracks := make([]QueueTrack, len(ids))
+	for i, id := range ids {
+		tracks[i] = QueueTrack{ID: id, Title: id}
+	}
+	return tracks
+}
+
+func TestAppendPastLimit(t *testing.T) {
+	styles := NewStyles(DefaultTheme())
+	q := NewQueue(&styles)
+	q.SetMaxLen(3)
+	q.Replace(queueTracks("a", "b", "c"), 2)
+
+	q.Append(queueTracks("d")...)
+	if c, ok := q.TakeCap(); !ok || c != (QueueCap{Limit: 3, Dropped: 1}) {
+		t.Errorf("TakeCap() = %+v, %v after dropping one played track", c, ok)
+	}
+	if _, ok := q.TakeCap(); ok {
+		t.Error("TakeCap() reported the same add twice")
+	}
+
+	// Everything after c is unplayed, so nothing more can go.
+	q.Append(queueTracks("e", "f", "g")...)
+	if c, ok := q.TakeCap(); !ok || c != (QueueCap{Limit: 3, Dropped: 1, Over: true}) {
+		t.Errorf("TakeCap() = %+v, %v with only unplayed tracks", c, ok)
+	}
+}